@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+type spec struct {
+	pkg      string
+	entities []entitySpec
+}
+
+type entitySpec struct {
+	name   string
+	fields []string
+}
+
+type model struct {
+	Package         string
+	StdImports      []string
+	Imports         []string
+	Definitions     []definitions
+	WorkspaceFields []field
+	Structs         []structDef
+}
+
+type definitions struct {
+	ListName string
+	JSON     string
+	Elem     string
+}
+
+type structDef struct {
+	Name   string
+	Fields []field
+}
+
+type field struct {
+	Name string
+	Type string
+	JSON string // the json tag of the modelv2 field, options included
+}
+
+var (
+	workspaceConfigsType = reflect.TypeFor[modelv2.WorkspaceConfigs]()
+	workspaceConfigType  = reflect.TypeFor[modelv2.WorkspaceConfig]()
+)
+
+// generator renders the types of the selected modelv2 fields, using the generated types instead of the modelv2 ones
+// for the selected entities and keeping track of the packages to import.
+type generator struct {
+	local   map[reflect.Type]string
+	imports map[string]struct{}
+}
+
+func generate(s spec) ([]byte, error) {
+	if s.pkg == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+	if len(s.entities) == 0 {
+		return nil, fmt.Errorf("at least one entity is required")
+	}
+
+	g := &generator{local: make(map[reflect.Type]string), imports: make(map[string]struct{})}
+	selected := make(map[string]entitySpec, len(s.entities))
+	for _, e := range s.entities {
+		if _, ok := selected[e.name]; ok {
+			return nil, fmt.Errorf("entity %q selected more than once", e.name)
+		}
+		if e.name == "workspaces" || e.name == "updatedAt" {
+			return nil, fmt.Errorf("entity %q is always included", e.name)
+		}
+		selected[e.name] = e
+	}
+
+	m := model{Package: s.pkg}
+	structs := make(map[string]entitySpec)
+	var structTypes []reflect.Type
+
+	// first register the types of the selected entities, so that they are rendered as the generated ones
+	for _, sf := range reflect.VisibleFields(workspaceConfigsType) {
+		e, ok := selected[jsonName(sf)]
+		if !ok {
+			continue
+		}
+		delete(selected, e.name)
+		elem := elemStruct(sf.Type)
+		g.local[elem] = elem.Name()
+		g.local[reflect.PointerTo(elem)] = "*" + elem.Name()
+		structs[elem.Name()] = e
+		structTypes = append(structTypes, elem)
+		m.Definitions = append(m.Definitions, definitions{ListName: sf.Name, JSON: sf.Tag.Get("json"), Elem: elem.Name()})
+	}
+	var workspaceFields []reflect.StructField
+	for _, sf := range reflect.VisibleFields(workspaceConfigType) {
+		e, ok := selected[jsonName(sf)]
+		if !ok {
+			continue
+		}
+		delete(selected, e.name)
+		workspaceFields = append(workspaceFields, sf)
+		elem := elemStruct(sf.Type)
+		if elem == nil {
+			if len(e.fields) > 0 {
+				return nil, fmt.Errorf("entity %q has no fields to select", e.name)
+			}
+			continue
+		}
+		g.local[elem] = elem.Name()
+		g.local[reflect.PointerTo(elem)] = "*" + elem.Name()
+		structs[elem.Name()] = e
+		structTypes = append(structTypes, elem)
+	}
+	if len(selected) > 0 {
+		unknown := make([]string, 0, len(selected))
+		for name := range selected {
+			unknown = append(unknown, name)
+		}
+		slices.Sort(unknown)
+		return nil, fmt.Errorf("unknown entities: %s", strings.Join(unknown, ", "))
+	}
+
+	for _, sf := range workspaceFields {
+		f, err := g.field(sf)
+		if err != nil {
+			return nil, err
+		}
+		m.WorkspaceFields = append(m.WorkspaceFields, f)
+	}
+	g.imports["time"] = struct{}{} // for the WorkspaceConfig UpdatedAt
+
+	for _, t := range structTypes {
+		sd, err := g.structDef(t, structs[t.Name()])
+		if err != nil {
+			return nil, err
+		}
+		m.Structs = append(m.Structs, sd)
+	}
+
+	g.imports["iter"] = struct{}{}
+	g.imports["github.com/rudderlabs/rudder-cp-sdk/diff"] = struct{}{}
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			m.Imports = append(m.Imports, imp)
+		} else {
+			m.StdImports = append(m.StdImports, imp)
+		}
+	}
+	slices.Sort(m.StdImports)
+	slices.Sort(m.Imports)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) structDef(t reflect.Type, e entitySpec) (structDef, error) {
+	all := len(e.fields) == 0
+	wanted := make(map[string]bool, len(e.fields))
+	for _, name := range e.fields {
+		wanted[name] = false
+	}
+	sd := structDef{Name: t.Name()}
+	for _, sf := range reflect.VisibleFields(t) {
+		name := jsonName(sf)
+		if name == "" || !sf.IsExported() {
+			continue
+		}
+		if _, ok := wanted[name]; !all && !ok {
+			continue
+		}
+		wanted[name] = true
+		f, err := g.field(sf)
+		if err != nil {
+			return structDef{}, err
+		}
+		sd.Fields = append(sd.Fields, f)
+	}
+	var unknown []string
+	for _, name := range e.fields {
+		if !wanted[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return structDef{}, fmt.Errorf("unknown fields of entity %q: %s", e.name, strings.Join(unknown, ", "))
+	}
+	return sd, nil
+}
+
+func (g *generator) field(sf reflect.StructField) (field, error) {
+	typ, err := g.typeName(sf.Type)
+	if err != nil {
+		return field{}, fmt.Errorf("field %s: %w", sf.Name, err)
+	}
+	return field{Name: sf.Name, Type: typ, JSON: sf.Tag.Get("json")}, nil
+}
+
+func (g *generator) typeName(t reflect.Type) (string, error) {
+	if local, ok := g.local[t]; ok {
+		return local, nil
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		g.imports[t.PkgPath()] = struct{}{}
+		return path.Base(t.PkgPath()) + "." + t.Name(), nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeName(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := g.typeName(t.Elem())
+		return "[]" + elem, err
+	case reflect.Map:
+		key, err := g.typeName(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeName(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any", nil
+		}
+	default:
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// elemStruct returns the struct type of the elements of a map, slice or pointer, or nil if they are not structs.
+func elemStruct(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Map || t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.PkgPath() != workspaceConfigType.PkgPath() {
+		return nil
+	}
+	return t
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by cpsdk-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- range .StdImports }}
+	"{{ . }}"
+{{- end }}
+{{ range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+var (
+	_ diff.UpdateableObject[string]                       = &WorkspaceConfigs{}
+	_ diff.UpdateableList[string, diff.UpdateableElement] = &Workspaces{}
+{{- range .Definitions }}
+	_ diff.NonUpdateablesList[string, any]                = &{{ .ListName }}{}
+{{- end }}
+)
+
+// WorkspaceConfigs represents workspace configurations of one or more workspaces{{ if .Definitions }}, as well as definitions shared by all of them{{ end }}.
+type WorkspaceConfigs struct {
+	// Workspaces is a map of workspace configurations. The key is a workspace ID.
+	Workspaces Workspaces ` + "`json:\"workspaces\"`" + `
+{{- range .Definitions }}
+	{{ .ListName }} {{ .ListName }} ` + "`json:\"{{ .JSON }}\"`" + `
+{{- end }}
+}
+
+func (wcs *WorkspaceConfigs) Updateables() iter.Seq[diff.UpdateableList[string, diff.UpdateableElement]] {
+	return func(yield func(diff.UpdateableList[string, diff.UpdateableElement]) bool) {
+		yield(&wcs.Workspaces)
+	}
+}
+
+func (wcs *WorkspaceConfigs) NonUpdateables() iter.Seq[diff.NonUpdateablesList[string, any]] {
+	return func(yield func(diff.NonUpdateablesList[string, any]) bool) {
+{{- range .Definitions }}
+		if !yield(&wcs.{{ .ListName }}) {
+			return
+		}
+{{- end }}
+	}
+}
+
+type Workspaces map[string]*WorkspaceConfig
+
+func (ws *Workspaces) Type() string { return "Workspaces" }
+func (ws *Workspaces) Length() int  { return len(*ws) }
+func (ws *Workspaces) Reset()       { *ws = make(map[string]*WorkspaceConfig) }
+
+func (ws *Workspaces) List() iter.Seq2[string, diff.UpdateableElement] {
+	return func(yield func(string, diff.UpdateableElement) bool) {
+		for key, wc := range *ws {
+			if !yield(key, wc) {
+				break
+			}
+		}
+	}
+}
+
+func (ws *Workspaces) GetElementByKey(id string) (diff.UpdateableElement, bool) {
+	wc, ok := (*ws)[id]
+	return wc, ok
+}
+
+func (ws *Workspaces) SetElementByKey(id string, object diff.UpdateableElement) {
+	if *ws == nil {
+		*ws = make(map[string]*WorkspaceConfig)
+	}
+	(*ws)[id] = object.(*WorkspaceConfig)
+}
+
+type WorkspaceConfig struct {
+{{- range .WorkspaceFields }}
+	{{ .Name }} {{ .Type }} ` + "`json:\"{{ .JSON }}\"`" + `
+{{- end }}
+	UpdatedAt time.Time ` + "`json:\"updatedAt\"`" + `
+}
+
+func (wc *WorkspaceConfig) GetUpdatedAt() time.Time { return wc.UpdatedAt }
+func (wc *WorkspaceConfig) IsNil() bool             { return wc == nil }
+{{- range .Definitions }}
+
+type {{ .ListName }} map[string]*{{ .Elem }}
+
+func (d *{{ .ListName }}) Type() string { return "{{ .ListName }}" }
+func (d *{{ .ListName }}) Reset()       { *d = make(map[string]*{{ .Elem }}) }
+func (d *{{ .ListName }}) SetElementByKey(id string, object any) {
+	(*d)[id] = object.(*{{ .Elem }})
+}
+
+func (d *{{ .ListName }}) List() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, def := range *d {
+			if !yield(key, def) {
+				break
+			}
+		}
+	}
+}
+{{- end }}
+{{- range .Structs }}
+
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`json:\"{{ .JSON }}\"`" + `
+{{- end }}
+}
+{{- end }}
+`))
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("example is up to date", func(t *testing.T) {
+		args := []string{
+			"-entity", "sources=name,writeKey,enabled,deleted,sourceDefinitionName",
+			"-entity", "destinations=name,enabled,destinationDefinitionName",
+			"-entity", "connections",
+			"-entity", "sourceDefinitions=displayName,category",
+		}
+		example, err := os.ReadFile("./internal/example/example.go")
+		require.NoError(t, err)
+		require.Contains(t, string(example), "\n//go:generate go run github.com/rudderlabs/rudder-cp-sdk/cmd/cpsdk-gen "+strings.Join(args, " ")+"\n",
+			"the arguments should match the go:generate directive of the example")
+
+		output := filepath.Join(t.TempDir(), "workspaceconfigs_gen.go")
+		t.Setenv("GOPACKAGE", "example")
+		require.NoError(t, run(append(args, "-output", output), os.Stderr))
+
+		expected, err := os.ReadFile("./internal/example/workspaceconfigs_gen.go")
+		require.NoError(t, err)
+		actual, err := os.ReadFile(output)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(actual), "run go generate ./... to update the example")
+	})
+
+	t.Run("all fields", func(t *testing.T) {
+		src, err := generate(spec{pkg: "test", entities: []entitySpec{{name: "accounts"}, {name: "libraries"}}})
+		require.NoError(t, err)
+		require.Contains(t, string(src), "\"github.com/rudderlabs/rudder-cp-sdk/modelv2\"")
+		require.Contains(t, string(src), "Accounts  map[string]*Account `json:\"accounts\"`")
+		require.Contains(t, string(src), "Libraries []*Library          `json:\"libraries\"`")
+		require.Contains(t, string(src), "Category      modelv2.AccountCategory `json:\"rudderCategory\"`")
+		require.Contains(t, string(src), "func (wcs *WorkspaceConfigs) NonUpdateables()")
+		require.NotContains(t, string(src), "SourceDefinitions")
+	})
+
+	t.Run("tag options are kept", func(t *testing.T) {
+		g := &generator{local: make(map[reflect.Type]string), imports: make(map[string]struct{})}
+		f, err := g.field(reflect.TypeFor[struct {
+			Name string `json:"name,omitempty"`
+		}]().Field(0))
+		require.NoError(t, err)
+		require.Equal(t, field{Name: "Name", Type: "string", JSON: "name,omitempty"}, f)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		spec     spec
+		expected string
+	}{
+		{
+			name:     "no package",
+			spec:     spec{entities: []entitySpec{{name: "sources"}}},
+			expected: "package name is required",
+		},
+		{
+			name:     "no entities",
+			spec:     spec{pkg: "test"},
+			expected: "at least one entity is required",
+		},
+		{
+			name:     "unknown entity",
+			spec:     spec{pkg: "test", entities: []entitySpec{{name: "sources"}, {name: "foo"}, {name: "bar"}}},
+			expected: "unknown entities: bar, foo",
+		},
+		{
+			name:     "unknown field",
+			spec:     spec{pkg: "test", entities: []entitySpec{{name: "sources", fields: []string{"name", "foo"}}}},
+			expected: `unknown fields of entity "sources": foo`,
+		},
+		{
+			name:     "duplicate entity",
+			spec:     spec{pkg: "test", entities: []entitySpec{{name: "sources"}, {name: "sources"}}},
+			expected: `entity "sources" selected more than once`,
+		},
+		{
+			name:     "always included entity",
+			spec:     spec{pkg: "test", entities: []entitySpec{{name: "updatedAt"}}},
+			expected: `entity "updatedAt" is always included`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := generate(tc.spec)
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
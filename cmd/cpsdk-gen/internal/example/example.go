@@ -0,0 +1,5 @@
+// Package example contains a model generated by cpsdk-gen for services that only need sources, their write keys and
+// their connections to destinations.
+package example
+
+//go:generate go run github.com/rudderlabs/rudder-cp-sdk/cmd/cpsdk-gen -entity sources=name,writeKey,enabled,deleted,sourceDefinitionName -entity destinations=name,enabled,destinationDefinitionName -entity connections -entity sourceDefinitions=displayName,category
@@ -0,0 +1,51 @@
+package example
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+)
+
+func TestGeneratedModel(t *testing.T) {
+	data, err := os.ReadFile("../../../../testdata/sample_namespace.json")
+	require.NoError(t, err)
+
+	response := &WorkspaceConfigs{}
+	require.NoError(t, jsonrs.Unmarshal(data, response))
+	require.Len(t, response.Workspaces, 2)
+	require.Equal(t, goldenWorkspace1, response.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"])
+	require.Equal(t, goldenWorkspace2, response.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"])
+	require.Equal(t, goldenSourceDefinitions, response.SourceDefinitions)
+
+	cache := &WorkspaceConfigs{}
+	updater := &diff.Updater[string]{}
+	updatedAt, updated, err := updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, time.Date(2024, 11, 27, 20, 13, 30, 647000000, time.UTC), updatedAt)
+	require.Equal(t, goldenWorkspace1, cache.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"])
+	require.Equal(t, goldenWorkspace2, cache.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"])
+	require.Equal(t, goldenSourceDefinitions, cache.SourceDefinitions)
+
+	// no updates, the cache should stay the same
+	response = &WorkspaceConfigs{}
+	require.NoError(t, jsonrs.Unmarshal([]byte(`{
+		"workspaces": {"2hCBi02C8xYS8Rsy1m9bJjTlKy6": null, "2bVMV2JiAJe42OXZrzyvJI75v0N": null},
+		"sourceDefinitions": {
+			"close_crm": {"displayName": "Close CRM", "category": "webhook"},
+			"singer-klaviyo": {"displayName": "Klaviyo", "category": "singer-protocol"}
+		}
+	}`), response))
+	updatedAt, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, time.Date(2024, 11, 27, 20, 13, 30, 647000000, time.UTC), updatedAt)
+	require.Equal(t, goldenWorkspace1, cache.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"])
+	require.Equal(t, goldenWorkspace2, cache.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"])
+}
@@ -0,0 +1,61 @@
+package example
+
+import "time"
+
+var goldenWorkspace1 = &WorkspaceConfig{
+	Sources: map[string]*Source{
+		"2hCDcJJGtZCIMDWjAyjsOg0W1Hr": {
+			Name:           "Source 1",
+			WriteKey:       "some-write-key-for-source-1",
+			Enabled:        true,
+			DefinitionName: "Javascript",
+		},
+	},
+	Destinations: map[string]*Destination{
+		"2i9lmpOsrP9KHqDR5hAlKWfNzb5": {Name: "Destination 1", Enabled: true, DefinitionName: "MP"},
+		"2hQdZuO7jPzgcw1uN9NaU1u2wPg": {Name: "Destination 2", Enabled: true, DefinitionName: "BQ"},
+	},
+	Connections: map[string]*Connection{
+		"2i9lmuu9TW8vT4YJ0rTTSMDtVJR": {
+			SourceID:         "2hCDcJJGtZCIMDWjAyjsOg0W1Hr",
+			DestinationID:    "2i9lmpOsrP9KHqDR5hAlKWfNzb5",
+			Enabled:          true,
+			ProcessorEnabled: true,
+		},
+		"2hQdZtIleMfPqvozvCYtGDdjDYa": {
+			SourceID:         "2hCDcJJGtZCIMDWjAyjsOg0W1Hr",
+			DestinationID:    "2hQdZuO7jPzgcw1uN9NaU1u2wPg",
+			Enabled:          true,
+			ProcessorEnabled: true,
+		},
+	},
+	UpdatedAt: time.Date(2024, 10, 16, 13, 35, 54, 830000000, time.UTC),
+}
+
+var goldenWorkspace2 = &WorkspaceConfig{
+	Sources: map[string]*Source{
+		"2jIY89R3Ul5PxVsynuYapfmgtfP": {
+			Name:           "Source 2",
+			WriteKey:       "some-write-key-for-source-2",
+			Enabled:        true,
+			DefinitionName: "bigquery",
+		},
+	},
+	Destinations: map[string]*Destination{
+		"2nqWMUk1fNNAw4rU3bHYcm58CZZ": {Name: "Destination 3", Enabled: false, DefinitionName: "VWO"},
+	},
+	Connections: map[string]*Connection{
+		"2i9I6qe9ALBTu37KKLc5nN8PwZT": {
+			SourceID:         "2jIY89R3Ul5PxVsynuYapfmgtfP",
+			DestinationID:    "2nqWMUk1fNNAw4rU3bHYcm58CZZ",
+			Enabled:          true,
+			ProcessorEnabled: true,
+		},
+	},
+	UpdatedAt: time.Date(2024, 11, 27, 20, 13, 30, 647000000, time.UTC),
+}
+
+var goldenSourceDefinitions = SourceDefinitions{
+	"close_crm":      {DisplayName: "Close CRM", Category: "webhook"},
+	"singer-klaviyo": {DisplayName: "Klaviyo", Category: "singer-protocol"},
+}
@@ -0,0 +1,119 @@
+// Code generated by cpsdk-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"iter"
+	"time"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+)
+
+var (
+	_ diff.UpdateableObject[string]                       = &WorkspaceConfigs{}
+	_ diff.UpdateableList[string, diff.UpdateableElement] = &Workspaces{}
+	_ diff.NonUpdateablesList[string, any]                = &SourceDefinitions{}
+)
+
+// WorkspaceConfigs represents workspace configurations of one or more workspaces, as well as definitions shared by all of them.
+type WorkspaceConfigs struct {
+	// Workspaces is a map of workspace configurations. The key is a workspace ID.
+	Workspaces        Workspaces        `json:"workspaces"`
+	SourceDefinitions SourceDefinitions `json:"sourceDefinitions"`
+}
+
+func (wcs *WorkspaceConfigs) Updateables() iter.Seq[diff.UpdateableList[string, diff.UpdateableElement]] {
+	return func(yield func(diff.UpdateableList[string, diff.UpdateableElement]) bool) {
+		yield(&wcs.Workspaces)
+	}
+}
+
+func (wcs *WorkspaceConfigs) NonUpdateables() iter.Seq[diff.NonUpdateablesList[string, any]] {
+	return func(yield func(diff.NonUpdateablesList[string, any]) bool) {
+		if !yield(&wcs.SourceDefinitions) {
+			return
+		}
+	}
+}
+
+type Workspaces map[string]*WorkspaceConfig
+
+func (ws *Workspaces) Type() string { return "Workspaces" }
+func (ws *Workspaces) Length() int  { return len(*ws) }
+func (ws *Workspaces) Reset()       { *ws = make(map[string]*WorkspaceConfig) }
+
+func (ws *Workspaces) List() iter.Seq2[string, diff.UpdateableElement] {
+	return func(yield func(string, diff.UpdateableElement) bool) {
+		for key, wc := range *ws {
+			if !yield(key, wc) {
+				break
+			}
+		}
+	}
+}
+
+func (ws *Workspaces) GetElementByKey(id string) (diff.UpdateableElement, bool) {
+	wc, ok := (*ws)[id]
+	return wc, ok
+}
+
+func (ws *Workspaces) SetElementByKey(id string, object diff.UpdateableElement) {
+	if *ws == nil {
+		*ws = make(map[string]*WorkspaceConfig)
+	}
+	(*ws)[id] = object.(*WorkspaceConfig)
+}
+
+type WorkspaceConfig struct {
+	Sources      map[string]*Source      `json:"sources"`
+	Destinations map[string]*Destination `json:"destinations"`
+	Connections  map[string]*Connection  `json:"connections"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+}
+
+func (wc *WorkspaceConfig) GetUpdatedAt() time.Time { return wc.UpdatedAt }
+func (wc *WorkspaceConfig) IsNil() bool             { return wc == nil }
+
+type SourceDefinitions map[string]*SourceDefinition
+
+func (d *SourceDefinitions) Type() string { return "SourceDefinitions" }
+func (d *SourceDefinitions) Reset()       { *d = make(map[string]*SourceDefinition) }
+func (d *SourceDefinitions) SetElementByKey(id string, object any) {
+	(*d)[id] = object.(*SourceDefinition)
+}
+
+func (d *SourceDefinitions) List() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, def := range *d {
+			if !yield(key, def) {
+				break
+			}
+		}
+	}
+}
+
+type SourceDefinition struct {
+	DisplayName string `json:"displayName"`
+	Category    string `json:"category"`
+}
+
+type Source struct {
+	Name           string `json:"name"`
+	WriteKey       string `json:"writeKey"`
+	Enabled        bool   `json:"enabled"`
+	Deleted        bool   `json:"deleted"`
+	DefinitionName string `json:"sourceDefinitionName"`
+}
+
+type Destination struct {
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	DefinitionName string `json:"destinationDefinitionName"`
+}
+
+type Connection struct {
+	SourceID         string `json:"sourceId"`
+	DestinationID    string `json:"destinationId"`
+	Enabled          bool   `json:"enabled"`
+	ProcessorEnabled bool   `json:"processorEnabled"`
+}
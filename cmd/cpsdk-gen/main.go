@@ -0,0 +1,56 @@
+// cpsdk-gen generates workspace configs models that only contain a subset of the entities and fields of
+// modelv2.WorkspaceConfigs, together with the plumbing needed to use them with diff.Updater and the poller.
+//
+// Entities are selected by their JSON name, optionally followed by the JSON names of the fields to keep, e.g.:
+//
+//	//go:generate go run github.com/rudderlabs/rudder-cp-sdk/cmd/cpsdk-gen -entity sources=name,writeKey -entity connections
+//
+// Workspace entities (e.g. sources, destinations, connections, accounts) become fields of the generated
+// WorkspaceConfig, while sourceDefinitions and destinationDefinitions become non updateable lists of the generated
+// WorkspaceConfigs. If no fields are given, all the fields of the entity are kept.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "cpsdk-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	var (
+		spec   = spec{pkg: os.Getenv("GOPACKAGE")}
+		output string
+	)
+
+	fs := flag.NewFlagSet("cpsdk-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&spec.pkg, "package", spec.pkg, "name of the package of the generated file (defaults to $GOPACKAGE)")
+	fs.StringVar(&output, "output", "workspaceconfigs_gen.go", "path of the generated file")
+	fs.Func("entity", "entity to include as name[=field,...], can be repeated", func(v string) error {
+		name, fields, _ := strings.Cut(v, "=")
+		e := entitySpec{name: name}
+		if fields != "" {
+			e.fields = strings.Split(fields, ",")
+		}
+		spec.entities = append(spec.entities, e)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := generate(spec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
@@ -0,0 +1,118 @@
+// cpsdk-validate validates the configs of the sources and destinations of workspace configs snapshots against their
+// definitions, and the references between their entities, e.g. in CI or before accepting new configs:
+//
+//	go run github.com/rudderlabs/rudder-cp-sdk/cmd/cpsdk-validate snapshot.json [snapshot.json...]
+//
+// Snapshots are JSON files in the format returned by the namespace and workspace endpoints. Entities whose definition
+// is not part of their snapshot are reported with a warning, unless the definition is provided by one of the files
+// of -definitions, in the same format:
+//
+//	cpsdk-validate -definitions definitions.json snapshot.json
+//
+// Findings are printed to stdout, and the exit status is 1 if any of them is an error (or a warning, with -strict).
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/validation"
+)
+
+var errInvalid = errors.New("invalid configs found")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errInvalid) {
+			_, _ = fmt.Fprintln(os.Stderr, "cpsdk-validate:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	var (
+		strict      bool
+		definitions = &modelv2.WorkspaceConfigs{}
+	)
+
+	fs := flag.NewFlagSet("cpsdk-validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&strict, "strict", false, "fail on warnings too")
+	fs.Func("definitions", "snapshot providing the definitions missing from the validated ones (repeatable)", func(path string) error {
+		wcs, err := readSnapshot(path)
+		if err != nil {
+			return err
+		}
+		for name, def := range wcs.SourceDefinitions {
+			setDefault(&definitions.SourceDefinitions, name, def)
+		}
+		for name, def := range wcs.DestinationDefinitions {
+			setDefault(&definitions.DestinationDefinitions, name, def)
+		}
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("at least one snapshot is required")
+	}
+
+	invalid := false
+	for _, path := range fs.Args() {
+		wcs, err := readSnapshot(path)
+		if err != nil {
+			return err
+		}
+		for name, def := range definitions.SourceDefinitions {
+			setDefault(&wcs.SourceDefinitions, name, def)
+		}
+		for name, def := range definitions.DestinationDefinitions {
+			setDefault(&wcs.DestinationDefinitions, name, def)
+		}
+		report := validation.Validate(wcs)
+		for _, e := range report.Entities {
+			for _, f := range e.Findings {
+				entity := fmt.Sprintf("%s %s", e.EntityType, e.EntityID)
+				if e.DefinitionName != "" {
+					entity += " (" + e.DefinitionName + ")"
+				}
+				_, _ = fmt.Fprintf(stdout, "%s: workspace %s: %s: %s\n", path, e.WorkspaceID, entity, f)
+				invalid = invalid || f.Severity == validation.SeverityError || strict
+			}
+		}
+	}
+	if invalid {
+		return errInvalid
+	}
+	return nil
+}
+
+func readSnapshot(path string) (*modelv2.WorkspaceConfigs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	wcs := &modelv2.WorkspaceConfigs{}
+	if err := jsonrs.Unmarshal(data, wcs); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return wcs, nil
+}
+
+// setDefault sets m[key] to value, unless m already has a value for key.
+func setDefault[M ~map[string]V, V any](m *M, key string, value V) {
+	if *m == nil {
+		*m = make(M)
+	}
+	if _, ok := (*m)[key]; !ok {
+		(*m)[key] = value
+	}
+}
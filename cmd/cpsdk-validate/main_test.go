@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{
+		"workspaces": {"ws-1": {"destinations": {"dst-1": {"destinationDefinitionName": "WEBHOOK", "config": {"url": "x", "extra": 1}}}}},
+		"destinationDefinitions": {"WEBHOOK": {"config": {"requiredKeys": ["url"], "destConfig": {"defaultConfig": ["url"]}}}}
+	}`), 0o644))
+
+	t.Run("warnings only", func(t *testing.T) {
+		var stdout bytes.Buffer
+		require.NoError(t, run([]string{valid}, &stdout, &bytes.Buffer{}))
+		require.Equal(t, valid+": workspace ws-1: destination dst-1 (WEBHOOK): warning: extra: unknown field\n", stdout.String())
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		require.ErrorIs(t, run([]string{"-strict", valid}, &bytes.Buffer{}, &bytes.Buffer{}), errInvalid)
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		var stdout bytes.Buffer
+		require.NoError(t, run([]string{"../../testdata/sample_namespace.json"}, &stdout, &bytes.Buffer{}),
+			"the sample has no errors, only definitions missing from it")
+		require.Contains(t, stdout.String(), `warning: destination definition "VWO" not found, config not validated`)
+		require.NotContains(t, stdout.String(), "error:")
+	})
+
+	t.Run("definitions", func(t *testing.T) {
+		definitions := filepath.Join(dir, "definitions.json")
+		require.NoError(t, os.WriteFile(definitions, []byte(`{
+			"destinationDefinitions": {"VWO": {"config": {"requiredKeys": ["accountId", "apiKey"]}}}
+		}`), 0o644))
+
+		var stdout bytes.Buffer
+		require.ErrorIs(t, run([]string{"-definitions", definitions, "../../testdata/sample_namespace.json"}, &stdout, &bytes.Buffer{}), errInvalid)
+		require.Contains(t, stdout.String(), "destination 2nqWMUk1fNNAw4rU3bHYcm58CZZ (VWO): error: apiKey: required field is missing")
+		require.NotContains(t, stdout.String(), `"VWO" not found`)
+	})
+
+	t.Run("references", func(t *testing.T) {
+		references := filepath.Join(dir, "references.json")
+		require.NoError(t, os.WriteFile(references, []byte(`{
+			"workspaces": {"ws-1": {"connections": {"conn-1": {"sourceId": "src-1", "destinationId": "dst-1"}}}}
+		}`), 0o644))
+
+		var stdout bytes.Buffer
+		require.ErrorIs(t, run([]string{references}, &stdout, &bytes.Buffer{}), errInvalid)
+		require.Equal(t, references+`: workspace ws-1: connection conn-1: error: destinationId: destination "dst-1" not found`+"\n"+
+			references+`: workspace ws-1: connection conn-1: error: sourceId: source "src-1" not found`+"\n", stdout.String())
+	})
+
+	t.Run("no snapshots", func(t *testing.T) {
+		require.EqualError(t, run(nil, &bytes.Buffer{}, &bytes.Buffer{}), "at least one snapshot is required")
+	})
+}
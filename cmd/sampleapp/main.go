@@ -65,6 +65,8 @@ func setupWorkspaceConfigsPoller[K comparable](
 		poller.WithPollingMaxElapsedTime[K](5*time.Minute),
 		poller.WithPollingMaxRetries[K](15),
 		poller.WithPollingBackoffMultiplier[K](1.5),
+		// refetch all workspace configs whenever the cache is found to be inconsistent with a response
+		poller.WithInconsistencyRecovery[K](nil),
 		poller.WithOnResponse[K](func(_ context.Context, updated bool, err error) {
 			if err != nil {
 				// e.g. bump metric on failure
@@ -114,7 +116,8 @@ func setupClientWithPoller(
 func run(ctx context.Context, log logger.Logger) error {
 	var (
 		// WARNING: if you don't want to use modelv2.WorkspaceConfigs because you're interested in a smaller subset of
-		// the data, then have a look at diff_test.go for an example of how to implement a custom UpdateableList.
+		// the data, then have a look at diff.MapList and diff.MapNonUpdateables (see lists_test.go for an example) or,
+		// if you need a custom UpdateableList, at diff_test.go for an example of how to implement one.
 		cache   = &modelv2.WorkspaceConfigs{}
 		cacheMu = &sync.RWMutex{}
 	)
@@ -0,0 +1,94 @@
+package cpsdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// coalescingClient makes concurrent GetWorkspaceConfigs calls with the same updatedAfter share a single request, and
+// the workspace configs decoded from its response, see [WithRequestCoalescing].
+type coalescingClient struct {
+	Client
+
+	mu    sync.Mutex
+	calls map[time.Time]*coalescedCall
+}
+
+type coalescedCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int // guarded by the mutex of the client
+
+	wcs *modelv2.WorkspaceConfigs
+	err error
+}
+
+func (c *coalescingClient) GetWorkspaceConfigs(ctx context.Context, object any, updatedAfter time.Time) error {
+	wcs, ok := object.(*modelv2.WorkspaceConfigs)
+	if !ok {
+		return c.Client.GetWorkspaceConfigs(ctx, object, updatedAfter)
+	}
+
+	key := coalescingKey(updatedAfter)
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		// the request outlives the context of the caller starting it, as long as other callers wait for it
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+		go c.do(callCtx, key, call, updatedAfter)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return call.err
+		}
+		// every caller gets its own copy, since callers are free to modify it
+		call.wcs.DeepCopyInto(wcs)
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if call.waiters--; call.waiters == 0 {
+			call.cancel()
+			c.forget(key, call)
+		}
+		return ctx.Err()
+	}
+}
+
+func (c *coalescingClient) GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error) {
+	return getAccountSecret(ctx, c.Client, accountID)
+}
+
+// coalescingKey returns the key of the calls with the given updatedAfter, which is comparable with == regardless of
+// its location and monotonic clock reading.
+func coalescingKey(updatedAfter time.Time) time.Time {
+	return updatedAfter.UTC().Round(0)
+}
+
+func (c *coalescingClient) do(ctx context.Context, key time.Time, call *coalescedCall, updatedAfter time.Time) {
+	defer call.cancel()
+	wcs := &modelv2.WorkspaceConfigs{}
+	err := c.Client.GetWorkspaceConfigs(ctx, wcs, updatedAfter)
+
+	c.mu.Lock()
+	c.forget(key, call)
+	c.mu.Unlock()
+	call.wcs, call.err = wcs, err
+	close(call.done)
+}
+
+// forget makes the following calls start a new request, unless it has already been done for this call.
+func (c *coalescingClient) forget(key time.Time, call *coalescedCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
@@ -0,0 +1,119 @@
+package cpsdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/testhelper/httptest"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestRequestCoalescing(t *testing.T) {
+	var (
+		requests  atomic.Int64
+		cancelled atomic.Int64
+		release   = make(chan struct{})
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			cancelled.Add(1)
+			return
+		}
+		_, _ = w.Write([]byte(`{"workspaces": {"ws-1": {"updatedAt": "2024-01-01T00:00:00Z", "settings": {"dataRetention": {"retentionPeriod": "default"}}}}}`))
+	}))
+	defer ts.Close()
+
+	cpSDK, err := New(
+		WithBaseUrl(ts.URL),
+		WithNamespaceIdentity("ns", "secret"),
+		WithRequestCoalescing(),
+	)
+	require.NoError(t, err)
+	client := cpSDK.Client.(*coalescingClient)
+	waiters := func(updatedAfter time.Time) int {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		if call, ok := client.calls[coalescingKey(updatedAfter)]; ok {
+			return call.waiters
+		}
+		return 0
+	}
+	updatedAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("concurrent calls share a request", func(t *testing.T) {
+		requests.Store(0)
+		var (
+			wg      sync.WaitGroup
+			results = make([]*modelv2.WorkspaceConfigs, 5)
+		)
+		for i := range results {
+			wg.Go(func() {
+				results[i] = &modelv2.WorkspaceConfigs{}
+				require.NoError(t, cpSDK.GetWorkspaceConfigs(context.Background(), results[i], updatedAfter.In(time.Local)))
+			})
+		}
+		// a caller giving up doesn't affect the others
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() { errs <- cpSDK.GetWorkspaceConfigs(ctx, &modelv2.WorkspaceConfigs{}, updatedAfter) }()
+		require.Eventually(t, func() bool { return waiters(updatedAfter) == 6 }, time.Second, time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-errs, context.Canceled)
+
+		release <- struct{}{}
+		wg.Wait()
+		require.EqualValues(t, 1, requests.Load())
+		for _, wcs := range results[1:] {
+			require.Equal(t, results[0], wcs)
+			require.NotSame(t, results[0].Workspaces["ws-1"], wcs.Workspaces["ws-1"], "callers get their own copy")
+		}
+		require.EqualValues(t, "default", results[0].Workspaces["ws-1"].Settings.DataRetention.RetentionPeriod)
+		require.Equal(t, 0, waiters(updatedAfter))
+	})
+
+	t.Run("cancelled when all callers give up", func(t *testing.T) {
+		requests.Store(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			errs <- cpSDK.GetWorkspaceConfigs(ctx, &modelv2.WorkspaceConfigs{}, updatedAfter.Add(time.Hour))
+		}()
+		require.Eventually(t, func() bool { return waiters(updatedAfter.Add(time.Hour)) == 1 }, time.Second, time.Millisecond)
+		// cancelling before the request reaches the server would leave nothing to cancel
+		require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-errs, context.Canceled)
+		require.Equal(t, 0, waiters(updatedAfter.Add(time.Hour)))
+		require.Eventually(t, func() bool { return cancelled.Load() == 1 }, time.Second, time.Millisecond,
+			"the request is cancelled")
+	})
+
+	t.Run("calls with different updatedAfter", func(t *testing.T) {
+		requests.Store(0)
+		var wg sync.WaitGroup
+		for _, ua := range []time.Time{updatedAfter, updatedAfter.Add(time.Second)} {
+			wg.Go(func() {
+				require.NoError(t, cpSDK.GetWorkspaceConfigs(context.Background(), &modelv2.WorkspaceConfigs{}, ua))
+			})
+		}
+		require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("other objects aren't coalesced", func(t *testing.T) {
+		var wcs map[string]any
+		require.NoError(t, cpSDK.GetWorkspaceConfigs(context.Background(), &wcs, time.Time{}))
+		require.Contains(t, wcs, "workspaces")
+	})
+}
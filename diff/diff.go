@@ -3,6 +3,10 @@ package diff
 import (
 	"fmt"
 	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -12,10 +16,17 @@ type UpdateableObject[K comparable] interface {
 }
 
 type UpdateableElement interface {
-	GetUpdatedAt() time.Time
+	Timestamped
 	IsNil() bool
 }
 
+// Timestamped is implemented by the elements that know when they were last updated, e.g. the entities of a
+// workspace config. It lets changes be detected without comparing the whole content of the elements, when their
+// updatedAt differ.
+type Timestamped interface {
+	GetUpdatedAt() time.Time
+}
+
 type UpdateableList[K comparable, T UpdateableElement] interface {
 	Type() string
 	Length() int
@@ -32,52 +43,92 @@ type NonUpdateablesList[K comparable, T any] interface {
 	List() iter.Seq2[K, T]
 }
 
+// InconsistencyError is returned by [Updater.UpdateCache] when the new object reports elements as not updated
+// (i.e. nil) that are either missing or nil in the cache. Retrying with the same updatedAt cannot resolve it,
+// the affected elements (or the whole object) have to be fetched again without an updatedAfter.
+type InconsistencyError[K comparable] struct {
+	// Keys maps the type of each updateable list to the keys of its inconsistent elements.
+	Keys map[string][]K
+}
+
+func (e *InconsistencyError[K]) Error() string {
+	types := make([]string, 0, len(e.Keys))
+	for t := range e.Keys {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	msgs := make([]string, 0, len(types))
+	for _, t := range types {
+		msgs = append(msgs, fmt.Sprintf(`values %v in %q were not updated but could not be found in cache`, e.Keys[t], t))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllKeys returns the keys of the inconsistent elements across all updateable lists.
+func (e *InconsistencyError[K]) AllKeys() []K {
+	var keys []K
+	for _, k := range e.Keys {
+		keys = append(keys, k...)
+	}
+	return keys
+}
+
+// Changes describes how the elements of a list in the cache changed after an update. Keys are in no particular order.
+type Changes[K comparable] struct {
+	Added   []K
+	Removed []K
+	Changed []K
+}
+
+// IsEmpty returns true if no element was added, removed or changed.
+func (c Changes[K]) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
 type Updater[K comparable] struct {
 	latestUpdatedAt time.Time
+	changes         map[string]Changes[K]
 }
 
 func (u *Updater[K]) UpdateCache(new, cache UpdateableObject[K]) (time.Time, bool, error) {
 	var (
-		countOfUpdatable int
 		atLeastOneUpdate bool
 		latestUpdatedAt  time.Time
+		changes          = make(map[string]Changes[K])
 	)
 
+	// Check for inconsistencies before touching either object, so that a failed update leaves both of them as they
+	// were and the new object can be patched and handled again.
+	if err := checkConsistency(new, cache); err != nil {
+		return time.Time{}, false, err
+	}
+	// A new object without elements is only valid if it removes the elements of the cache, e.g. once all of them
+	// were deleted, otherwise it is either not valid or an empty response.
+	if isEmpty(new) && isEmpty(cache) {
+		return time.Time{}, false, fmt.Errorf("no updateable lists found in new object")
+	}
+
 	for n := range new.Updateables() {
-		if n.Length() != 0 {
-			countOfUpdatable++
-		}
 		var (
-			found, updated bool
-			c              UpdateableList[K, UpdateableElement]
+			updated     bool
+			listChanges Changes[K]
 		)
-		for c = range cache.Updateables() {
-			if n.Type() == c.Type() {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return time.Time{}, false, fmt.Errorf(`cannot find updateable list of type %q in cache`, n.Type())
-		}
+		c, _ := findUpdateableList(cache, n.Type())
 
 		for k, v := range n.List() {
 			if v.IsNil() {
-				cachedValue, ok := c.GetElementByKey(k)
-				if !ok {
-					return time.Time{}, false, fmt.Errorf(`value "%v" in %q was not updated but was not present in cache`, k, n.Type())
-				}
-
-				if cachedValue.IsNil() {
-					return time.Time{}, false, fmt.Errorf(`value "%v" in %q was not updated but was nil in cache`, k, n.Type())
-				}
-
+				cachedValue, _ := c.GetElementByKey(k)
 				n.SetElementByKey(k, cachedValue)
 
 				continue
 			}
 
 			updated = true // at least one value in "new" was not null, thus it was updated
+			if _, ok := c.GetElementByKey(k); ok {
+				listChanges.Changed = append(listChanges.Changed, k)
+			} else {
+				listChanges.Added = append(listChanges.Added, k)
+			}
 
 			if v.GetUpdatedAt().After(latestUpdatedAt) {
 				latestUpdatedAt = v.GetUpdatedAt()
@@ -91,6 +142,13 @@ func (u *Updater[K]) UpdateCache(new, cache UpdateableObject[K]) (time.Time, boo
 		if updated || n.Length() != c.Length() {
 			atLeastOneUpdate = true
 
+			for k := range c.List() {
+				if _, ok := n.GetElementByKey(k); !ok {
+					listChanges.Removed = append(listChanges.Removed, k)
+				}
+			}
+			changes[n.Type()] = listChanges
+
 			c.Reset()
 			// we need to iterate over the cache too because we can't simply do "cache = new", since it's an interface
 			// it won't persist after the function ends.
@@ -100,13 +158,15 @@ func (u *Updater[K]) UpdateCache(new, cache UpdateableObject[K]) (time.Time, boo
 		}
 	}
 
-	// if there are no updatable lists, that means that the new object is not valid or we got an empty response
-	if countOfUpdatable == 0 {
-		return time.Time{}, false, fmt.Errorf("no updateable lists found in new object")
-	}
-	if err := u.replaceNonUpdateables(new, cache); err != nil {
+	nonUpdateablesChanges, err := u.replaceNonUpdateables(new, cache)
+	if err != nil {
 		return time.Time{}, false, err
 	}
+	if len(nonUpdateablesChanges) > 0 {
+		atLeastOneUpdate = true
+	}
+	maps.Copy(changes, nonUpdateablesChanges)
+	u.changes = changes
 
 	// only update updatedAt if we managed to handle the response
 	// so that we don't miss any updates in case of an error
@@ -119,7 +179,73 @@ func (u *Updater[K]) UpdateCache(new, cache UpdateableObject[K]) (time.Time, boo
 	return u.latestUpdatedAt, atLeastOneUpdate, nil
 }
 
-func (u *Updater[K]) replaceNonUpdateables(new, cache UpdateableObject[K]) error {
+// checkConsistency makes sure that every updateable list of new has a counterpart in cache and that every element
+// reported as not updated can be found in it. It returns an [InconsistencyError] listing all the elements that can't.
+func checkConsistency[K comparable](new, cache UpdateableObject[K]) error {
+	var inconsistent map[string][]K
+	for n := range new.Updateables() {
+		c, found := findUpdateableList(cache, n.Type())
+		if !found {
+			return fmt.Errorf(`cannot find updateable list of type %q in cache`, n.Type())
+		}
+		for k, v := range n.List() {
+			if !v.IsNil() {
+				continue
+			}
+			if cachedValue, ok := c.GetElementByKey(k); ok && !cachedValue.IsNil() {
+				continue
+			}
+			if inconsistent == nil {
+				inconsistent = make(map[string][]K)
+			}
+			inconsistent[n.Type()] = append(inconsistent[n.Type()], k)
+		}
+	}
+	if inconsistent != nil {
+		return &InconsistencyError[K]{Keys: inconsistent}
+	}
+	return nil
+}
+
+// isEmpty returns true if none of the updateable lists of obj has any element.
+func isEmpty[K comparable](obj UpdateableObject[K]) bool {
+	for l := range obj.Updateables() {
+		if l.Length() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func findUpdateableList[K comparable](obj UpdateableObject[K], typ string) (UpdateableList[K, UpdateableElement], bool) {
+	for l := range obj.Updateables() {
+		if l.Type() == typ {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// Changes returns the changes applied to the cache by the last successful call to UpdateCache, keyed by list type.
+// Lists that did not change are omitted. For updateable lists, an element is reported as changed whenever a new
+// version of it was received, while the elements of non updateable lists are compared by content (or by updatedAt,
+// see [Timestamped]). The returned map is a copy, which can be modified freely.
+func (u *Updater[K]) Changes() map[string]Changes[K] {
+	if u.changes == nil {
+		return nil
+	}
+	changes := make(map[string]Changes[K], len(u.changes))
+	for typ, c := range u.changes {
+		changes[typ] = Changes[K]{Added: slices.Clone(c.Added), Removed: slices.Clone(c.Removed), Changed: slices.Clone(c.Changed)}
+	}
+	return changes
+}
+
+// replaceNonUpdateables brings the non updateable lists of the cache in line with the ones of the new object.
+// Elements are compared by content (or by updatedAt, see [Timestamped]), so that the ones that did not change keep
+// being the same values in the cache (e.g. the same pointers) and lists without changes are not touched at all.
+func (u *Updater[K]) replaceNonUpdateables(new, cache UpdateableObject[K]) (map[string]Changes[K], error) {
+	var changes map[string]Changes[K]
 	for n := range new.NonUpdateables() {
 		var (
 			found bool
@@ -132,14 +258,103 @@ func (u *Updater[K]) replaceNonUpdateables(new, cache UpdateableObject[K]) error
 			}
 		}
 		if !found {
-			return fmt.Errorf(`cannot find non updateable list of type %q in cache`, n.Type())
+			return nil, fmt.Errorf(`cannot find non updateable list of type %q in cache`, n.Type())
+		}
+
+		cached := make(map[K]any)
+		for k, v := range c.List() {
+			cached[k] = v
 		}
 
-		c.Reset()
+		var (
+			listChanges Changes[K]
+			values      = make(map[K]any)
+		)
 		for k, v := range n.List() {
-			c.SetElementByKey(k, v)
+			cachedValue, ok := cached[k]
+			switch {
+			case !ok:
+				listChanges.Added = append(listChanges.Added, k)
+			case !unchanged(cachedValue, v):
+				listChanges.Changed = append(listChanges.Changed, k)
+			default:
+				v = cachedValue
+			}
+			values[k] = v
+		}
+		for k := range cached {
+			if _, ok := values[k]; !ok {
+				listChanges.Removed = append(listChanges.Removed, k)
+			}
 		}
+		if listChanges.IsEmpty() {
+			continue
+		}
+
+		if len(listChanges.Removed) > 0 || len(cached) == 0 {
+			// there is no way to delete single elements, plus an empty list might not even be initialized
+			c.Reset()
+			for k, v := range values {
+				c.SetElementByKey(k, v)
+			}
+		} else {
+			for _, k := range slices.Concat(listChanges.Added, listChanges.Changed) {
+				c.SetElementByKey(k, values[k])
+			}
+		}
+
+		if changes == nil {
+			changes = make(map[string]Changes[K])
+		}
+		changes[n.Type()] = listChanges
 	}
 
-	return nil
+	return changes, nil
+}
+
+// unchanged returns true if a and b have the same content. [Timestamped] elements with different non-zero updatedAt
+// are known to have changed without comparing their content, while the content of the other ones is always compared,
+// since it can change without updatedAt being bumped.
+func unchanged(a, b any) bool {
+	if ta, ok := timestamped(a); ok {
+		if tb, ok := timestamped(b); ok && !ta.GetUpdatedAt().IsZero() && !tb.GetUpdatedAt().IsZero() &&
+			!ta.GetUpdatedAt().Equal(tb.GetUpdatedAt()) {
+			return false
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// timestamped returns v as a [Timestamped], unless it isn't one or it is a nil pointer.
+func timestamped(v any) (Timestamped, bool) {
+	t, ok := v.(Timestamped)
+	if !ok {
+		return nil, false
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, false
+	}
+	return t, true
+}
+
+// CompareByUpdatedAt returns how the elements of a map changed between two versions of it, e.g. the sources of two
+// versions of a workspace config. Elements with different non-zero updatedAt are considered changed, while the other
+// ones are compared by content. Keys are in no particular order.
+func CompareByUpdatedAt[K comparable, V Timestamped](old, new map[K]V) Changes[K] {
+	var changes Changes[K]
+	for k, v := range new {
+		oldValue, ok := old[k]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, k)
+		case !unchanged(oldValue, v):
+			changes.Changed = append(changes.Changed, k)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			changes.Removed = append(changes.Removed, k)
+		}
+	}
+	return changes
 }
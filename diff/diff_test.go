@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"iter"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -216,15 +217,270 @@ func TestUpdateable(t *testing.T) {
 		require.Len(t, dstDefinitions, 1)
 	}
 
-	// in the sixth call we didn't receive any updates, so the cache should remain the same, and we should receive an error
+	// in the sixth call everything was deleted, so the cache should be emptied
 	sixthCall := []byte(`{}`)
 	response = &WorkspaceConfigs{}
 	err = jsonrs.Unmarshal(sixthCall, &response)
 	require.NoError(t, err)
 	updateAfter, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated, "a removal should be reported as an update")
+	require.Equal(t, time.Date(2021, 9, 1, 6, 6, 8, 0, time.UTC), updateAfter)
+	require.Equal(t, []string{"workspace1"}, updater.Changes()["Workspaces"].Removed)
+	require.Empty(t, getWorkspaces(cache))
+
+	// in the seventh call we didn't receive anything while there is nothing to remove, so we should receive an error
+	response = &WorkspaceConfigs{}
+	err = jsonrs.Unmarshal([]byte(`{}`), &response)
+	require.NoError(t, err)
+	updateAfter, updated, err = updater.UpdateCache(response, cache)
 	require.Equal(t, time.Time{}, updateAfter)
 	require.False(t, updated)
-	require.Error(t, err)
+	require.EqualError(t, err, "no updateable lists found in new object")
+}
+
+func TestUpdateCacheInconsistency(t *testing.T) {
+	firstCall, err := os.ReadFile("./testdata/call_01.json")
+	require.NoError(t, err)
+
+	response := &WorkspaceConfigs{}
+	require.NoError(t, jsonrs.Unmarshal(firstCall, response))
+
+	cache := &WorkspaceConfigs{}
+	updater := &Updater[string]{}
+	updatedAt, _, err := updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+
+	// workspace1 is not updated, while workspace3 is reported as not updated although it was never cached
+	response = &WorkspaceConfigs{Workspaces: Workspaces{
+		"workspace1": nil,
+		"workspace2": {UpdatedAt: updatedAt.Add(time.Second)},
+		"workspace3": nil,
+	}}
+	newUpdatedAt, updated, err := updater.UpdateCache(response, cache)
+	require.Zero(t, newUpdatedAt)
+	require.False(t, updated)
+	var inconsistency *InconsistencyError[string]
+	require.ErrorAs(t, err, &inconsistency)
+	require.Equal(t, map[string][]string{"Workspaces": {"workspace3"}}, inconsistency.Keys)
+	require.Equal(t, []string{"workspace3"}, inconsistency.AllKeys())
+	require.EqualError(t, err, `values [workspace3] in "Workspaces" were not updated but could not be found in cache`)
+
+	// neither the response nor the cache should have been touched
+	require.Nil(t, response.Workspaces["workspace1"])
+	require.Equal(t, goldenWorkspace2, cache.Workspaces["workspace2"])
+
+	// once the inconsistent workspace is patched the response can be handled
+	response.Workspaces["workspace3"] = goldenWorkspace3
+	newUpdatedAt, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, updatedAt.Add(time.Second), newUpdatedAt)
+	require.Len(t, cache.Workspaces, 3)
+	require.Equal(t, goldenWorkspace1, cache.Workspaces["workspace1"])
+	require.Equal(t, goldenWorkspace3, cache.Workspaces["workspace3"])
+}
+
+func TestUpdateCacheChanges(t *testing.T) {
+	var (
+		updatedAt = time.Date(2021, 9, 1, 1, 2, 3, 0, time.UTC)
+		cache     = &WorkspaceConfigs{}
+		updater   = &Updater[string]{}
+	)
+	newResponse := func(srcDefinitions SourceDefinitions) *WorkspaceConfigs {
+		return &WorkspaceConfigs{
+			Workspaces:        Workspaces{"workspace1": {UpdatedAt: updatedAt}},
+			SourceDefinitions: srcDefinitions,
+		}
+	}
+
+	_, updated, err := updater.UpdateCache(newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook"},
+	}), cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, map[string]Changes[string]{
+		"Workspaces":        {Added: []string{"workspace1"}},
+		"SourceDefinitions": {Added: []string{"close_crm", "webhook"}},
+	}, sortedChanges(updater.Changes()))
+	changes := updater.Changes()
+	changes["SourceDefinitions"].Added[0] = "modified"
+	delete(changes, "Workspaces")
+	require.Equal(t, map[string]Changes[string]{
+		"Workspaces":        {Added: []string{"workspace1"}},
+		"SourceDefinitions": {Added: []string{"close_crm", "webhook"}},
+	}, sortedChanges(updater.Changes()), "changes are returned as a copy")
+	closeCRM, webhook := cache.SourceDefinitions["close_crm"], cache.SourceDefinitions["webhook"]
+
+	// same definitions, decoded again: the cache should keep the same pointers and no changes should be reported
+	response := newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook"},
+	})
+	response.Workspaces["workspace1"] = nil
+	_, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Empty(t, updater.Changes())
+	require.Same(t, closeCRM, cache.SourceDefinitions["close_crm"])
+	require.Same(t, webhook, cache.SourceDefinitions["webhook"])
+
+	// one definition changed and one added: the unchanged one should keep its pointer
+	response = newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook v2"},
+		"klaviyo":   {Name: "Klaviyo"},
+	})
+	response.Workspaces["workspace1"] = nil
+	_, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated, "a definitions change should be reported as an update")
+	require.Equal(t, map[string]Changes[string]{
+		"SourceDefinitions": {Added: []string{"klaviyo"}, Changed: []string{"webhook"}},
+	}, sortedChanges(updater.Changes()))
+	require.Same(t, closeCRM, cache.SourceDefinitions["close_crm"])
+	require.Equal(t, &SourceDefinition{Name: "Webhook v2"}, cache.SourceDefinitions["webhook"])
+	require.Len(t, cache.SourceDefinitions, 3)
+
+	// one definition removed
+	response = newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook v2"},
+	})
+	response.Workspaces["workspace1"] = nil
+	_, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, map[string]Changes[string]{
+		"SourceDefinitions": {Removed: []string{"klaviyo"}},
+	}, sortedChanges(updater.Changes()))
+	require.Same(t, closeCRM, cache.SourceDefinitions["close_crm"])
+	require.Len(t, cache.SourceDefinitions, 2)
+
+	// workspaces added, updated and removed
+	response = newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook v2"},
+	})
+	response.Workspaces = Workspaces{
+		"workspace2": {UpdatedAt: updatedAt},
+		"workspace3": {UpdatedAt: updatedAt},
+	}
+	_, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, map[string]Changes[string]{
+		"Workspaces": {Added: []string{"workspace2", "workspace3"}, Removed: []string{"workspace1"}},
+	}, sortedChanges(updater.Changes()))
+
+	response = newResponse(SourceDefinitions{
+		"close_crm": {Name: "Close CRM"},
+		"webhook":   {Name: "Webhook v2"},
+	})
+	response.Workspaces = Workspaces{
+		"workspace2": nil,
+		"workspace3": {UpdatedAt: updatedAt.Add(time.Second)},
+	}
+	_, updated, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, map[string]Changes[string]{
+		"Workspaces": {Changed: []string{"workspace3"}},
+	}, sortedChanges(updater.Changes()))
+}
+
+func TestUpdateCacheTimestampedChanges(t *testing.T) {
+	var (
+		updatedAt = time.Date(2021, 9, 1, 1, 2, 3, 0, time.UTC)
+		defs      = MapNonUpdateables[string, *timestampedDefinition]{}
+		cache     = NewObject[string](
+			[]UpdateableList[string, UpdateableElement]{&MapList[string, *WorkspaceConfig]{}},
+			&defs,
+		)
+		updater = &Updater[string]{}
+	)
+	newResponse := func(defs MapNonUpdateables[string, *timestampedDefinition]) *Object[string] {
+		return NewObject[string](
+			[]UpdateableList[string, UpdateableElement]{&MapList[string, *WorkspaceConfig]{"workspace1": {UpdatedAt: updatedAt}}},
+			&defs,
+		)
+	}
+
+	_, _, err := updater.UpdateCache(newResponse(MapNonUpdateables[string, *timestampedDefinition]{
+		"a": {Name: "A", UpdatedAt: updatedAt},
+		"b": {Name: "B", UpdatedAt: updatedAt},
+		"c": {Name: "C"},
+	}), cache)
+	require.NoError(t, err)
+	a := defs["a"]
+
+	// elements with different updatedAt are changed, the other ones are compared by content
+	_, _, err = updater.UpdateCache(newResponse(MapNonUpdateables[string, *timestampedDefinition]{
+		"a": {Name: "A", UpdatedAt: updatedAt},
+		"b": {Name: "B v2", UpdatedAt: updatedAt.Add(time.Second)},
+		"c": {Name: "C v2"},
+	}), cache)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Changes[string]{
+		"*diff.timestampedDefinition": {Changed: []string{"b", "c"}},
+		"*diff.WorkspaceConfig":       {Changed: []string{"workspace1"}},
+	}, sortedChanges(updater.Changes()))
+	require.Same(t, a, defs["a"])
+	require.Equal(t, "B v2", defs["b"].Name)
+	require.Equal(t, "C v2", defs["c"].Name)
+
+	// content changing without an updatedAt bump is still detected
+	_, _, err = updater.UpdateCache(newResponse(MapNonUpdateables[string, *timestampedDefinition]{
+		"a": {Name: "A v2", UpdatedAt: updatedAt},
+		"b": {Name: "B v2", UpdatedAt: updatedAt.Add(time.Second)},
+		"c": {Name: "C v2"},
+	}), cache)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Changes[string]{
+		"*diff.timestampedDefinition": {Changed: []string{"a"}},
+		"*diff.WorkspaceConfig":       {Changed: []string{"workspace1"}},
+	}, sortedChanges(updater.Changes()))
+	require.Equal(t, "A v2", defs["a"].Name)
+}
+
+func TestCompareByUpdatedAt(t *testing.T) {
+	updatedAt := time.Date(2021, 9, 1, 1, 2, 3, 0, time.UTC)
+	old := map[string]*timestampedDefinition{
+		"same":       {Name: "same", UpdatedAt: updatedAt},
+		"updated":    {Name: "updated", UpdatedAt: updatedAt},
+		"no-time":    {Name: "no-time"},
+		"no-time-eq": {Name: "no-time-eq"},
+		"touched":    {Name: "touched", UpdatedAt: updatedAt},
+		"nil":        nil,
+		"removed":    {Name: "removed", UpdatedAt: updatedAt},
+	}
+	new := map[string]*timestampedDefinition{
+		"same":       {Name: "same", UpdatedAt: updatedAt},
+		"touched":    {Name: "touched v2", UpdatedAt: updatedAt},
+		"updated":    {Name: "updated", UpdatedAt: updatedAt.Add(time.Second)},
+		"no-time":    {Name: "no-time v2"},
+		"no-time-eq": {Name: "no-time-eq"},
+		"nil":        {Name: "nil", UpdatedAt: updatedAt},
+		"added":      {Name: "added", UpdatedAt: updatedAt},
+	}
+	changes := CompareByUpdatedAt(old, new)
+	slices.Sort(changes.Changed)
+	require.Equal(t, Changes[string]{
+		Added:   []string{"added"},
+		Removed: []string{"removed"},
+		Changed: []string{"nil", "no-time", "touched", "updated"},
+	}, changes)
+	require.True(t, CompareByUpdatedAt(old, old).IsEmpty())
+}
+
+func sortedChanges(changes map[string]Changes[string]) map[string]Changes[string] {
+	for _, c := range changes {
+		slices.Sort(c.Added)
+		slices.Sort(c.Removed)
+		slices.Sort(c.Changed)
+	}
+	return changes
 }
 
 type WorkspaceConfigs struct {
@@ -327,6 +583,13 @@ type ConnectionReplay struct {
 	DestinationID string `json:"destinationId"`
 }
 
+type timestampedDefinition struct {
+	Name      string
+	UpdatedAt time.Time
+}
+
+func (d *timestampedDefinition) GetUpdatedAt() time.Time { return d.UpdatedAt }
+
 type SourceDefinition struct {
 	Name string `json:"name"`
 }
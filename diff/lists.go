@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"iter"
+	"reflect"
+)
+
+var (
+	_ UpdateableList[string, UpdateableElement] = &MapList[string, UpdateableElement]{}
+	_ NonUpdateablesList[string, any]           = &MapNonUpdateables[string, any]{}
+	_ UpdateableObject[string]                  = &Object[string]{}
+)
+
+// MapList is an UpdateableList backed by a map, e.g. a map of workspace configs keyed by workspace ID.
+// Its type is the name of its element type, thus an object must not have two lists with the same element type.
+type MapList[K comparable, V UpdateableElement] map[K]V
+
+func (l *MapList[K, V]) Type() string { return reflect.TypeFor[V]().String() }
+func (l *MapList[K, V]) Length() int  { return len(*l) }
+func (l *MapList[K, V]) Reset()       { *l = make(map[K]V) }
+
+func (l *MapList[K, V]) List() iter.Seq2[K, UpdateableElement] {
+	return func(yield func(K, UpdateableElement) bool) {
+		for key, v := range *l {
+			if !yield(key, v) {
+				break
+			}
+		}
+	}
+}
+
+func (l *MapList[K, V]) GetElementByKey(id K) (UpdateableElement, bool) {
+	v, ok := (*l)[id]
+	return v, ok
+}
+
+func (l *MapList[K, V]) SetElementByKey(id K, object UpdateableElement) {
+	if *l == nil {
+		*l = make(map[K]V)
+	}
+	(*l)[id] = object.(V)
+}
+
+// MapNonUpdateables is a NonUpdateablesList backed by a map, e.g. a map of source definitions keyed by name.
+// Its type is the name of its element type, thus an object must not have two lists with the same element type.
+type MapNonUpdateables[K comparable, V any] map[K]V
+
+func (l *MapNonUpdateables[K, V]) Type() string { return reflect.TypeFor[V]().String() }
+func (l *MapNonUpdateables[K, V]) Reset()       { *l = make(map[K]V) }
+
+func (l *MapNonUpdateables[K, V]) SetElementByKey(id K, object any) {
+	if *l == nil {
+		*l = make(map[K]V)
+	}
+	(*l)[id] = object.(V)
+}
+
+func (l *MapNonUpdateables[K, V]) List() iter.Seq2[K, any] {
+	return func(yield func(K, any) bool) {
+		for key, v := range *l {
+			if !yield(key, v) {
+				break
+			}
+		}
+	}
+}
+
+// Updateables returns a sequence of the given lists, to be returned by [UpdateableObject.Updateables].
+func Updateables[K comparable](lists ...UpdateableList[K, UpdateableElement]) iter.Seq[UpdateableList[K, UpdateableElement]] {
+	return func(yield func(UpdateableList[K, UpdateableElement]) bool) {
+		for _, l := range lists {
+			if !yield(l) {
+				return
+			}
+		}
+	}
+}
+
+// NonUpdateables returns a sequence of the given lists, to be returned by [UpdateableObject.NonUpdateables].
+func NonUpdateables[K comparable](lists ...NonUpdateablesList[K, any]) iter.Seq[NonUpdateablesList[K, any]] {
+	return func(yield func(NonUpdateablesList[K, any]) bool) {
+		for _, l := range lists {
+			if !yield(l) {
+				return
+			}
+		}
+	}
+}
+
+// Object is an UpdateableObject made of a fixed set of lists, for when the lists are not fields of a single struct.
+type Object[K comparable] struct {
+	UpdateableLists    []UpdateableList[K, UpdateableElement]
+	NonUpdateableLists []NonUpdateablesList[K, any]
+}
+
+// NewObject returns an Object made of the given updateable and non updateable lists.
+func NewObject[K comparable](
+	updateables []UpdateableList[K, UpdateableElement], nonUpdateables ...NonUpdateablesList[K, any],
+) *Object[K] {
+	return &Object[K]{UpdateableLists: updateables, NonUpdateableLists: nonUpdateables}
+}
+
+func (o *Object[K]) Updateables() iter.Seq[UpdateableList[K, UpdateableElement]] {
+	return Updateables(o.UpdateableLists...)
+}
+
+func (o *Object[K]) NonUpdateables() iter.Seq[NonUpdateablesList[K, any]] {
+	return NonUpdateables(o.NonUpdateableLists...)
+}
@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"iter"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+func TestMapLists(t *testing.T) {
+	updater := &Updater[string]{}
+	cache := &mapWorkspaceConfigs{}
+
+	for i, tc := range []struct {
+		file               string
+		expectedUpdatedAt  time.Time
+		expectedWorkspaces map[string]*WorkspaceConfig
+		expectedSrcDefs    int
+		expectedDstDefs    int
+	}{
+		{
+			file:               "call_01.json",
+			expectedUpdatedAt:  time.Date(2021, 9, 1, 6, 6, 6, 0, time.UTC),
+			expectedWorkspaces: map[string]*WorkspaceConfig{"workspace1": goldenWorkspace1, "workspace2": goldenWorkspace2},
+			expectedSrcDefs:    1,
+		},
+		{
+			file:               "call_02.json",
+			expectedUpdatedAt:  time.Date(2021, 9, 1, 6, 6, 6, 0, time.UTC),
+			expectedWorkspaces: map[string]*WorkspaceConfig{"workspace1": goldenWorkspace1, "workspace2": goldenWorkspace2},
+			expectedSrcDefs:    1,
+		},
+		{
+			file:               "call_03.json",
+			expectedUpdatedAt:  time.Date(2021, 9, 1, 6, 6, 7, 0, time.UTC),
+			expectedWorkspaces: map[string]*WorkspaceConfig{"workspace1": goldenWorkspace1, "workspace3": goldenWorkspace3},
+			expectedSrcDefs:    1,
+		},
+		{
+			file:               "call_04.json",
+			expectedUpdatedAt:  time.Date(2021, 9, 1, 6, 6, 8, 0, time.UTC),
+			expectedWorkspaces: map[string]*WorkspaceConfig{"workspace1": goldenWorkspace1, "workspace3": goldenUpdatedWorkspace3},
+			expectedSrcDefs:    2,
+			expectedDstDefs:    1,
+		},
+		{
+			file:               "call_05.json",
+			expectedUpdatedAt:  time.Date(2021, 9, 1, 6, 6, 8, 0, time.UTC),
+			expectedWorkspaces: map[string]*WorkspaceConfig{"workspace1": goldenWorkspace1},
+			expectedSrcDefs:    2,
+			expectedDstDefs:    1,
+		},
+	} {
+		data, err := os.ReadFile("./testdata/" + tc.file)
+		require.NoError(t, err)
+
+		response := &mapWorkspaceConfigs{}
+		require.NoError(t, jsonrs.Unmarshal(data, response))
+
+		updatedAt, _, err := updater.UpdateCache(response, cache)
+		require.NoErrorf(t, err, "call %d", i+1)
+		require.Equalf(t, tc.expectedUpdatedAt, updatedAt, "call %d", i+1)
+		require.Equalf(t, MapList[string, *WorkspaceConfig](tc.expectedWorkspaces), cache.Workspaces, "call %d", i+1)
+		require.Lenf(t, cache.SourceDefinitions, tc.expectedSrcDefs, "call %d", i+1)
+		require.Lenf(t, cache.DestinationDefinitions, tc.expectedDstDefs, "call %d", i+1)
+	}
+
+	require.Equal(t, "*diff.WorkspaceConfig", cache.Workspaces.Type())
+	require.Equal(t, "*diff.SourceDefinition", cache.SourceDefinitions.Type())
+	require.Equal(t, &SourceDefinition{Name: "Klaviyo"}, cache.SourceDefinitions["singer-klaviyo"])
+	require.Equal(t, &DestinationDefinition{Name: "LinkedIn Ads"}, cache.DestinationDefinitions["LINKEDIN_ADS"])
+}
+
+func TestObject(t *testing.T) {
+	var (
+		workspaces = MapList[string, *WorkspaceConfig]{}
+		srcDefs    = MapNonUpdateables[string, *SourceDefinition]{}
+		dstDefs    = MapNonUpdateables[string, *DestinationDefinition]{}
+		cache      = NewObject[string](
+			[]UpdateableList[string, UpdateableElement]{&workspaces},
+			&srcDefs, &dstDefs,
+		)
+		updatedAt = time.Date(2021, 9, 1, 1, 2, 3, 0, time.UTC)
+	)
+
+	response := &mapWorkspaceConfigs{
+		Workspaces:        MapList[string, *WorkspaceConfig]{"workspace1": {UpdatedAt: updatedAt}},
+		SourceDefinitions: MapNonUpdateables[string, *SourceDefinition]{"close_crm": {Name: "Close CRM"}},
+	}
+	latestUpdatedAt, updated, err := (&Updater[string]{}).UpdateCache(response, cache)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, updatedAt, latestUpdatedAt)
+	require.Equal(t, response.Workspaces, workspaces)
+	require.Equal(t, response.SourceDefinitions, srcDefs)
+}
+
+// mapWorkspaceConfigs is the same as WorkspaceConfigs, but implemented with the generic map lists.
+type mapWorkspaceConfigs struct {
+	Workspaces             MapList[string, *WorkspaceConfig]                 `json:"workspaces"`
+	SourceDefinitions      MapNonUpdateables[string, *SourceDefinition]      `json:"sourceDefinitions"`
+	DestinationDefinitions MapNonUpdateables[string, *DestinationDefinition] `json:"destinationDefinitions"`
+}
+
+func (wcs *mapWorkspaceConfigs) Updateables() iter.Seq[UpdateableList[string, UpdateableElement]] {
+	return Updateables[string](&wcs.Workspaces)
+}
+
+func (wcs *mapWorkspaceConfigs) NonUpdateables() iter.Seq[NonUpdateablesList[string, any]] {
+	return NonUpdateables[string](&wcs.SourceDefinitions, &wcs.DestinationDefinitions)
+}
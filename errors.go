@@ -9,6 +9,16 @@ import (
 // ErrUnsupportedOperation is returned when an operation is not supported for the current identity type.
 var ErrUnsupportedOperation = base.ErrUnsupportedOperation
 
+// ErrResponseTooLarge is returned when a response body is larger than the maximum size set by [WithMaxResponseSize].
+var ErrResponseTooLarge = base.ErrResponseTooLarge
+
+// ErrDecodeTimeout is returned when a response body takes longer to decode than the maximum duration set by
+// [WithMaxDecodeDuration].
+var ErrDecodeTimeout = base.ErrDecodeTimeout
+
+// UnexpectedContentTypeError is returned when a successful response is not JSON, see [WithContentTypeCheck].
+type UnexpectedContentTypeError = base.UnexpectedContentTypeError
+
 // UnexpectedStatusCodeError is returned when the control plane returns a non-200 status code. It includes the status code and first bytes of the response body for debugging purposes.
 type UnexpectedStatusCodeError = base.UnexpectedStatusCodeError
 
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/rudderlabs/rudder-go-kit/httputil"
 )
@@ -15,6 +16,24 @@ const updatedAfterTimeFormat = "2006-01-02T15:04:05.000Z"
 type Client struct {
 	HTTPClient HTTPClient
 	BaseURL    *url.URL
+	// Failover, if set, sends requests to fallback base URLs when BaseURL is unavailable.
+	Failover *Failover
+	// RateLimiter, if set, limits the rate of the requests sent, including those to fallback base URLs.
+	RateLimiter *RateLimiter
+	// InFlight, if set, is a semaphore capping the number of requests in flight at its capacity. A request is in
+	// flight until its response body is closed.
+	InFlight chan struct{}
+	// MaxResponseSize, if positive, is the maximum size of the response bodies, larger ones failing to be read with
+	// ErrResponseTooLarge.
+	MaxResponseSize int64
+	// MaxDecodeDuration, if positive, is the maximum time to read, thus decode, a response body once its headers are
+	// received, reading it failing with ErrDecodeTimeout past it.
+	MaxDecodeDuration time.Duration
+	// CheckContentType makes successful responses fail with UnexpectedContentTypeError unless they are JSON.
+	CheckContentType bool
+	// ErrorBodyLimit is the maximum number of bytes of the response body kept by UnexpectedStatusCodeError, see
+	// [NewUnexpectedStatusCodeError].
+	ErrorBodyLimit int64
 }
 
 type HTTPClient interface {
@@ -43,13 +62,32 @@ func (c *Client) Get(ctx context.Context, path string, queryOpts ...QueryOption)
 
 // Send the request and return the response body if the status code is 200 OK, otherwise return an error.
 func (c *Client) Send(req *http.Request) (io.ReadCloser, error) {
+	if c.Failover != nil {
+		return c.Failover.send(c, req)
+	}
+	return c.send(req)
+}
+
+func (c *Client) send(req *http.Request) (io.ReadCloser, error) {
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
+		release()
 		return nil, err
 	}
 	if res.StatusCode != http.StatusOK {
+		defer release()
 		defer func() { httputil.CloseResponse(res) }()
-		return nil, NewUnexpectedStatusCodeError(res)
+		return nil, NewUnexpectedStatusCodeError(res, c.ErrorBodyLimit)
+	}
+	body, err := c.guardBody(res)
+	if err != nil {
+		defer release()
+		defer func() { httputil.CloseResponse(res) }()
+		return nil, err
 	}
-	return res.Body, nil
+	return &releasingBody{ReadCloser: body, release: release}, nil
 }
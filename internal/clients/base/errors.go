@@ -1,6 +1,7 @@
 package base
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -8,12 +9,31 @@ import (
 	"github.com/cenkalti/backoff/v5"
 
 	"github.com/rudderlabs/rudder-go-kit/bytesize"
+
+	"github.com/rudderlabs/rudder-cp-sdk/internal/redact"
 )
 
 // ErrUnsupportedOperation is returned when an operation is not supported for the current identity type.
 var ErrUnsupportedOperation = fmt.Errorf("operation not supported for this client")
 
+// ErrResponseTooLarge is returned when a response body is larger than the maximum size of the client.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrDecodeTimeout is returned when a response body is read, e.g. decoded, for longer than the maximum decode duration
+// of the client.
+var ErrDecodeTimeout = errors.New("response body decode timeout")
+
+// UnexpectedContentTypeError is returned when a successful response is not JSON, e.g. an HTML page of a proxy.
+type UnexpectedContentTypeError struct {
+	ContentType string
+}
+
+func (e *UnexpectedContentTypeError) Error() string {
+	return fmt.Sprintf("unexpected content type: %q", e.ContentType)
+}
+
 // UnexpectedStatusCodeError is returned when the control plane returns a non-200 status code. It includes the status code and first bytes of the response body for debugging purposes.
+// Secret looking values of the body are redacted, bodies without any being kept as they are.
 type UnexpectedStatusCodeError struct {
 	StatusCode int
 	Body       []byte
@@ -23,13 +43,20 @@ func (e *UnexpectedStatusCodeError) Error() string {
 	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, string(e.Body))
 }
 
+// DefaultErrorBodyLimit is the number of bytes of the response body kept by [UnexpectedStatusCodeError] by default.
+const DefaultErrorBodyLimit = bytesize.MB
+
 // NewUnexpectedStatusCodeError creates a new UnexpectedStatusCodeError from the given HTTP response.
-// It reads the response body and includes it in the error for debugging purposes.
-func NewUnexpectedStatusCodeError(res *http.Response) error {
-	body, _ := io.ReadAll(io.LimitReader(res.Body, bytesize.MB))
+// It reads up to bodyLimit bytes of the response body, or [DefaultErrorBodyLimit] if not positive, and includes them
+// in the error for debugging purposes, with their secrets redacted.
+func NewUnexpectedStatusCodeError(res *http.Response, bodyLimit int64) error {
+	if bodyLimit <= 0 {
+		bodyLimit = DefaultErrorBodyLimit
+	}
+	body, _ := io.ReadAll(io.LimitReader(res.Body, bodyLimit))
 	return &UnexpectedStatusCodeError{
 		StatusCode: res.StatusCode,
-		Body:       body,
+		Body:       redact.JSON(body),
 	}
 }
 
@@ -0,0 +1,114 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Failover makes a [Client] send its requests to fallback base URLs when its base URL, the primary one, fails with a
+// connection error or a 5xx status code. Once a fallback serves a response, the following requests are sent to it
+// first, while the primary base URL is probed again every ProbeInterval.
+type Failover struct {
+	Fallbacks []*url.URL
+	// ProbeInterval is the minimum time between attempts to go back to the primary base URL. Zero makes every request
+	// try the primary base URL first.
+	ProbeInterval time.Duration
+	// OnResponse, if set, is called after every attempt with the base URL the request was sent to, and the error
+	// returned by it, if any.
+	OnResponse func(baseURL *url.URL, err error)
+
+	mu       sync.Mutex
+	active   int // index of the base URL requests are sent to first, 0 being the primary one
+	probedAt time.Time
+}
+
+func (f *Failover) send(c *Client, req *http.Request) (io.ReadCloser, error) {
+	// requests are built against the primary base URL, see [Client.Url]
+	path := strings.TrimPrefix(req.URL.EscapedPath(), c.BaseURL.EscapedPath())
+	var err error
+	for _, i := range f.order() {
+		baseURL := c.BaseURL
+		if i > 0 {
+			baseURL = f.Fallbacks[i-1]
+		}
+		var (
+			r    *http.Request
+			body io.ReadCloser
+		)
+		if r, err = withBaseURL(req, baseURL, path); err != nil {
+			return nil, &PermanenentError{Err: fmt.Errorf("creating request: %w", err)}
+		}
+		body, err = c.send(r)
+		if f.OnResponse != nil {
+			f.OnResponse(baseURL, err)
+		}
+		if err == nil {
+			f.served(i)
+			return body, nil
+		}
+		if !shouldFailover(req.Context(), err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// order returns the indexes of the base URLs to try, starting from the active one, or from the primary one when it
+// is time to probe it.
+func (f *Failover) order() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.Fallbacks) + 1
+	order := make([]int, 0, n)
+	for i := range n {
+		order = append(order, (f.active+i)%n)
+	}
+	if f.active > 0 && time.Since(f.probedAt) >= f.ProbeInterval {
+		f.probedAt = time.Now()
+		order = append([]int{0}, slices.DeleteFunc(order, func(i int) bool { return i == 0 })...)
+	}
+	return order
+}
+
+func (f *Failover) served(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i != f.active {
+		f.active = i
+		f.probedAt = time.Now()
+	}
+}
+
+// withBaseURL returns a copy of the request sent to the given base URL, with the same path and query.
+func withBaseURL(req *http.Request, baseURL *url.URL, path string) (*http.Request, error) {
+	// going through the string form, like [Client.Url] does, gives the path a leading slash when baseURL has none
+	u, err := url.Parse(baseURL.JoinPath(path).String())
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = req.URL.RawQuery
+	r := req.Clone(req.Context())
+	r.URL, r.Host = u, u.Host
+	return r, nil
+}
+
+// shouldFailover tells whether an error is caused by the base URL being unavailable or misbehaving, rather than by the
+// request, its context, or a response that would be too large anyway.
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrResponseTooLarge) {
+		return false
+	}
+	var statusErr *UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
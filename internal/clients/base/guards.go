@@ -0,0 +1,107 @@
+package base
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// guardBody checks the content type and size of a successful response, returning its body guarded against being too
+// large or too slow to read.
+func (c *Client) guardBody(res *http.Response) (io.ReadCloser, error) {
+	if c.CheckContentType {
+		if err := checkContentType(res.Header.Get("Content-Type")); err != nil {
+			return nil, err
+		}
+	}
+	body := res.Body
+	if c.MaxResponseSize > 0 {
+		if res.ContentLength > c.MaxResponseSize {
+			return nil, fmt.Errorf("%w: content length %d exceeds %d bytes", ErrResponseTooLarge, res.ContentLength, c.MaxResponseSize)
+		}
+		body = &limitedBody{ReadCloser: body, max: c.MaxResponseSize, remaining: c.MaxResponseSize}
+	}
+	if c.MaxDecodeDuration > 0 {
+		body = newDeadlineBody(body, c.MaxDecodeDuration)
+	}
+	return body, nil
+}
+
+func checkContentType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return &UnexpectedContentTypeError{ContentType: contentType}
+	}
+	return nil
+}
+
+// limitedBody fails with ErrResponseTooLarge once more than max bytes are read, unlike [io.LimitReader] which
+// silently truncates.
+type limitedBody struct {
+	io.ReadCloser
+	max       int64
+	remaining int64 // negative once the limit is exceeded
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.tooLarge()
+	}
+	// read one byte more than allowed, to tell a body of exactly max bytes from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+	n, b.remaining = int(b.remaining), -1
+	return n, b.tooLarge()
+}
+
+func (b *limitedBody) tooLarge() error {
+	return fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, b.max)
+}
+
+// deadlineBody fails with ErrDecodeTimeout once it has been read for longer than max, closing the underlying body to
+// interrupt a pending read.
+type deadlineBody struct {
+	io.ReadCloser
+	max     time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+func newDeadlineBody(body io.ReadCloser, max time.Duration) *deadlineBody {
+	b := &deadlineBody{ReadCloser: body, max: max}
+	b.timer = time.AfterFunc(max, func() {
+		b.expired.Store(true)
+		_ = body.Close()
+	})
+	return b
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	if b.expired.Load() {
+		return 0, b.timeout()
+	}
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && b.expired.Load() {
+		return n, b.timeout()
+	}
+	return n, err
+}
+
+func (b *deadlineBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
+
+func (b *deadlineBody) timeout() error {
+	return fmt.Errorf("%w: %s", ErrDecodeTimeout, b.max)
+}
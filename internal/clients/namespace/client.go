@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/rudderlabs/rudder-go-kit/jsonrs"
 
 	"github.com/rudderlabs/rudder-cp-sdk/identity"
 	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/base"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
 )
 
 type Client struct {
@@ -75,3 +77,21 @@ func (c *Client) getWorkspaceConfigsReader(ctx context.Context, updatedAfter tim
 	}
 	return c.Send(req)
 }
+
+func (c *Client) GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error) {
+	req, err := c.GetWithAuth(ctx, "/configuration/v2/namespaces/"+c.Identity.Namespace+"/accounts/"+url.PathEscape(accountID)+"/secret")
+	if err != nil {
+		return nil, fmt.Errorf("creating get request: %w", err)
+	}
+	reader, err := c.Send(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending get request: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	var secret modelv2.AccountSecret
+	if err = jsonrs.NewDecoder(reader).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("decoding account secret response: %w", err)
+	}
+	return &secret, nil
+}
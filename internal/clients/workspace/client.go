@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/rudderlabs/rudder-go-kit/jsonrs"
 
 	"github.com/rudderlabs/rudder-cp-sdk/identity"
 	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/base"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
 )
 
 type Client struct {
@@ -61,3 +63,21 @@ func (c *Client) GetWorkspaceConfigs(ctx context.Context, object any, updatedAft
 func (c *Client) GetNamespaceWorkspaces(ctx context.Context) (workspaceIDs []string, err error) {
 	return nil, base.ErrUnsupportedOperation
 }
+
+func (c *Client) GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error) {
+	req, err := c.Get(ctx, "/data-plane/v2/accounts/"+url.PathEscape(accountID)+"/secret")
+	if err != nil {
+		return nil, fmt.Errorf("creating get request: %w", err)
+	}
+	reader, err := c.Send(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending get request: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	var secret modelv2.AccountSecret
+	if err = jsonrs.NewDecoder(reader).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("decoding account secret response: %w", err)
+	}
+	return &secret, nil
+}
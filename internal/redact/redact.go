@@ -0,0 +1,96 @@
+// Package redact replaces the secret looking values of configs and JSON documents, e.g. of the bodies of error
+// responses, with a placeholder. It has no dependencies on the model, so that the clients can use it too.
+package redact
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// Value replaces the values of redacted secrets.
+const Value = "[REDACTED]"
+
+// secretKeyFragments are the fragments of config keys that are always considered secret, regardless of the
+// definition of the entity, once lowercased and stripped of separators.
+var secretKeyFragments = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credential", "accesskey", "writekey"}
+
+// secretJSONValue matches the string values of secret looking keys in JSON documents that cannot be parsed, e.g.
+// because they are truncated.
+var secretJSONValue = regexp.MustCompile(`(?i)("[^"]*(?:password|passwd|secret|token|api_?key|private_?key|credential|access_?key|write_?key)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// IsSecretKey returns true if a config key looks like it holds a secret, e.g. "password" or "api_key".
+func IsSecretKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+	for _, fragment := range secretKeyFragments {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Config returns a deep copy of a config, with the values of secret looking keys (see [IsSecretKey]) and of the
+// given top level secret keys replaced by [Value].
+func Config(config map[string]any, secretKeys ...string) map[string]any {
+	if config == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(config))
+	for key, value := range config {
+		if value != nil && (IsSecretKey(key) || slices.Contains(secretKeys, key)) {
+			redacted[key] = Value
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return Config(v)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// hasSecrets returns true if a value decoded from JSON holds non null values of secret looking keys.
+func hasSecrets(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if value != nil && IsSecretKey(key) || hasSecrets(value) {
+				return true
+			}
+		}
+	case []any:
+		return slices.ContainsFunc(v, hasSecrets)
+	}
+	return false
+}
+
+// JSON redacts the secret looking values of a JSON document. Documents without secrets are returned as they are,
+// while the others are encoded again, thus their keys may be reordered. Documents that cannot be parsed are redacted
+// on a best effort basis.
+func JSON(data []byte) []byte {
+	var v any
+	if err := jsonrs.Unmarshal(data, &v); err == nil {
+		if !hasSecrets(v) {
+			return data
+		}
+		if redacted, err := jsonrs.Marshal(redactValue(v)); err == nil {
+			return redacted
+		}
+	}
+	return secretJSONValue.ReplaceAll(data, []byte(`${1}"`+Value+`"`))
+}
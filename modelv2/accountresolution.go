@@ -0,0 +1,252 @@
+package modelv2
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// ErrNoAccount is returned when resolving the account of an entity that doesn't reference any.
+var ErrNoAccount = errors.New("no account referenced")
+
+// AccountReference describes a reference to an account from a field of another entity of the same workspace.
+type AccountReference struct {
+	// EntityType is the type of the referencing entity, e.g. "source" or "whtProject".
+	EntityType string
+	EntityID   string
+	// Field is the JSON name of the field holding the account ID, e.g. "gitRepoAccountId".
+	Field     string
+	AccountID string
+	// Category is the category the account is expected to have.
+	Category AccountCategory
+	// Role is the role the account is expected to have, if any.
+	Role string
+	// SecretVersion is the version of the account secrets the entity is expected to use, if any.
+	SecretVersion int
+}
+
+func (r AccountReference) String() string {
+	return fmt.Sprintf("%s %q (%s)", r.EntityType, r.EntityID, r.Field)
+}
+
+// MissingAccountError is returned when a referenced account is not part of the workspace.
+type MissingAccountError struct {
+	Reference AccountReference
+}
+
+func (e *MissingAccountError) Error() string {
+	return fmt.Sprintf("account %q referenced by %s not found", e.Reference.AccountID, e.Reference)
+}
+
+// AccountCategoryError is returned when a referenced account doesn't have the expected category.
+type AccountCategoryError struct {
+	Reference AccountReference
+	Category  AccountCategory
+}
+
+func (e *AccountCategoryError) Error() string {
+	return fmt.Sprintf("account %q referenced by %s has category %q instead of %q",
+		e.Reference.AccountID, e.Reference, e.Category, e.Reference.Category)
+}
+
+// AccountRoleError is returned when a referenced account doesn't have the expected role.
+type AccountRoleError struct {
+	Reference AccountReference
+	Role      string
+}
+
+func (e *AccountRoleError) Error() string {
+	return fmt.Sprintf("account %q referenced by %s has role %q instead of %q",
+		e.Reference.AccountID, e.Reference, e.Role, e.Reference.Role)
+}
+
+// StaleSecretError is returned when the secret version of an entity differs from the one of its account, meaning
+// that one of them was not updated after the account secrets changed.
+type StaleSecretError struct {
+	Reference     AccountReference
+	SecretVersion int
+}
+
+func (e *StaleSecretError) Error() string {
+	return fmt.Sprintf("account %q referenced by %s has secret version %d instead of %d",
+		e.Reference.AccountID, e.Reference, e.SecretVersion, e.Reference.SecretVersion)
+}
+
+// ResolveAccount returns the account referenced by ref, checking that it matches the expectations of ref.
+// Accounts without a category or role are not checked against the expected ones. In case of a
+// [*StaleSecretError] the account is returned together with the error.
+func (wc *WorkspaceConfig) ResolveAccount(ref AccountReference) (*Account, error) {
+	if ref.AccountID == "" {
+		return nil, fmt.Errorf("%s: %w", ref, ErrNoAccount)
+	}
+	account := wc.Accounts[ref.AccountID]
+	if account == nil {
+		return nil, &MissingAccountError{Reference: ref}
+	}
+	if ref.Category != "" && account.Category != "" && account.Category != ref.Category {
+		return nil, &AccountCategoryError{Reference: ref, Category: account.Category}
+	}
+	if ref.Role != "" && account.Role != "" && !strings.EqualFold(account.Role, ref.Role) {
+		return nil, &AccountRoleError{Reference: ref, Role: account.Role}
+	}
+	if ref.SecretVersion != 0 && account.SecretVersion != 0 && account.SecretVersion != ref.SecretVersion {
+		return account, &StaleSecretError{Reference: ref, SecretVersion: account.SecretVersion}
+	}
+	return account, nil
+}
+
+// SourceAccount returns the account of a source, which is expected to be a source account whose role is the
+// definition name of the source, with the same secret version as the source.
+func (wc *WorkspaceConfig) SourceAccount(sourceID string) (*Account, error) {
+	source := wc.Sources[sourceID]
+	if source == nil {
+		return nil, fmt.Errorf("source %q not found", sourceID)
+	}
+	return wc.ResolveAccount(sourceAccountReference(sourceID, source))
+}
+
+// WHTProjectGitRepoAccount returns the account of the git repository of a WHT project, which is expected to be a WHT
+// account.
+func (wc *WorkspaceConfig) WHTProjectGitRepoAccount(projectID string) (*Account, error) {
+	project := wc.WHTProjects[projectID]
+	if project == nil {
+		return nil, fmt.Errorf("WHT project %q not found", projectID)
+	}
+	return wc.ResolveAccount(whtGitRepoAccountReference(projectID, project))
+}
+
+// WHTProjectWarehouseAccount returns the account of the warehouse of a WHT project, which is expected to be a WHT
+// account.
+func (wc *WorkspaceConfig) WHTProjectWarehouseAccount(projectID string) (*Account, error) {
+	project := wc.WHTProjects[projectID]
+	if project == nil {
+		return nil, fmt.Errorf("WHT project %q not found", projectID)
+	}
+	return wc.ResolveAccount(whtWarehouseAccountReference(projectID, project))
+}
+
+// SQLModelVersionAccount returns the account a SQL model version runs with, which is expected to be a source account.
+func (wc *WorkspaceConfig) SQLModelVersionAccount(versionID string) (*Account, error) {
+	version := wc.SQLModelVersions[versionID]
+	if version == nil {
+		return nil, fmt.Errorf("SQL model version %q not found", versionID)
+	}
+	return wc.ResolveAccount(sqlModelVersionAccountReference(versionID, version))
+}
+
+// AudienceSourceAccount returns the account of an audience source, which is expected to be a source account.
+func (wc *WorkspaceConfig) AudienceSourceAccount(audienceSourceID string) (*Account, error) {
+	for _, as := range wc.AudienceSources {
+		if as != nil && as.ID == audienceSourceID {
+			return wc.ResolveAccount(audienceSourceAccountReference(as))
+		}
+	}
+	return nil, fmt.Errorf("audience source %q not found", audienceSourceID)
+}
+
+// AccountReferences returns the references to accounts of all the entities of the workspace that are not deleted.
+// Entities without an account are skipped.
+func (wc *WorkspaceConfig) AccountReferences() iter.Seq[AccountReference] {
+	return func(yield func(AccountReference) bool) {
+		for sourceID, source := range wc.Sources {
+			if source == nil || source.Deleted || source.AccountID == "" {
+				continue
+			}
+			if !yield(sourceAccountReference(sourceID, source)) {
+				return
+			}
+		}
+		for projectID, project := range wc.WHTProjects {
+			if project == nil || project.Deleted {
+				continue
+			}
+			if project.GitRepoAccountID != "" && !yield(whtGitRepoAccountReference(projectID, project)) {
+				return
+			}
+			if project.WarehouseAccountID != "" && !yield(whtWarehouseAccountReference(projectID, project)) {
+				return
+			}
+		}
+		for versionID, version := range wc.SQLModelVersions {
+			if version == nil || version.AccountID == "" {
+				continue
+			}
+			if !yield(sqlModelVersionAccountReference(versionID, version)) {
+				return
+			}
+		}
+		for _, as := range wc.AudienceSources {
+			if as == nil || as.Deleted || as.AccountID == "" {
+				continue
+			}
+			if !yield(audienceSourceAccountReference(as)) {
+				return
+			}
+		}
+	}
+}
+
+// CheckAccounts resolves all the [WorkspaceConfig.AccountReferences] of the workspace, returning the errors
+// encountered, e.g. missing accounts or stale secrets.
+func (wc *WorkspaceConfig) CheckAccounts() []error {
+	var errs []error
+	for ref := range wc.AccountReferences() {
+		if _, err := wc.ResolveAccount(ref); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func sourceAccountReference(sourceID string, source *Source) AccountReference {
+	return AccountReference{
+		EntityType:    "source",
+		EntityID:      sourceID,
+		Field:         "accountId",
+		AccountID:     source.AccountID,
+		Category:      AccountCategorySource,
+		Role:          source.DefinitionName,
+		SecretVersion: source.SecretVersion,
+	}
+}
+
+func whtGitRepoAccountReference(projectID string, project *WHTProject) AccountReference {
+	return AccountReference{
+		EntityType: "whtProject",
+		EntityID:   projectID,
+		Field:      "gitRepoAccountId",
+		AccountID:  project.GitRepoAccountID,
+		Category:   AccountCategoryWHT,
+	}
+}
+
+func whtWarehouseAccountReference(projectID string, project *WHTProject) AccountReference {
+	return AccountReference{
+		EntityType: "whtProject",
+		EntityID:   projectID,
+		Field:      "warehouseAccountId",
+		AccountID:  project.WarehouseAccountID,
+		Category:   AccountCategoryWHT,
+	}
+}
+
+func sqlModelVersionAccountReference(versionID string, version *SQLModelVersion) AccountReference {
+	return AccountReference{
+		EntityType: "sqlModelVersion",
+		EntityID:   versionID,
+		Field:      "accountId",
+		AccountID:  version.AccountID,
+		Category:   AccountCategorySource,
+	}
+}
+
+func audienceSourceAccountReference(as *AudienceSource) AccountReference {
+	return AccountReference{
+		EntityType: "audienceSource",
+		EntityID:   as.ID,
+		Field:      "accountId",
+		AccountID:  as.AccountID,
+		Category:   AccountCategorySource,
+	}
+}
@@ -0,0 +1,110 @@
+package modelv2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestAccountResolution(t *testing.T) {
+	wc := &modelv2.WorkspaceConfig{
+		Sources: map[string]*modelv2.Source{
+			"src-ok":       {DefinitionName: "singer-facebook-marketing", AccountID: "acc-source", SecretVersion: 4},
+			"src-stale":    {DefinitionName: "singer-facebook-marketing", AccountID: "acc-source", SecretVersion: 3},
+			"src-role":     {DefinitionName: "bigquery", AccountID: "acc-source"},
+			"src-missing":  {AccountID: "acc-unknown"},
+			"src-none":     {},
+			"src-category": {AccountID: "acc-wht"},
+			"src-deleted":  {AccountID: "acc-unknown", Deleted: true},
+		},
+		WHTProjects: map[string]*modelv2.WHTProject{
+			"wht-1": {GitRepoAccountID: "acc-wht", WarehouseAccountID: "acc-source"},
+		},
+		SQLModelVersions: map[string]*modelv2.SQLModelVersion{
+			"sql-1": {AccountID: "acc-uncategorized"},
+		},
+		AudienceSources: []*modelv2.AudienceSource{
+			{ID: "aud-1", AccountID: "acc-source"},
+			{ID: "aud-2", AccountID: "acc-unknown", Deleted: true},
+		},
+		Accounts: map[string]*modelv2.Account{
+			"acc-source":        {Role: "singer-facebook-marketing", Category: modelv2.AccountCategorySource, SecretVersion: 4},
+			"acc-wht":           {Category: modelv2.AccountCategoryWHT},
+			"acc-uncategorized": {},
+		},
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		account, err := wc.SourceAccount("src-ok")
+		require.NoError(t, err)
+		require.Same(t, wc.Accounts["acc-source"], account)
+
+		account, err = wc.WHTProjectGitRepoAccount("wht-1")
+		require.NoError(t, err)
+		require.Same(t, wc.Accounts["acc-wht"], account)
+
+		account, err = wc.SQLModelVersionAccount("sql-1")
+		require.NoError(t, err)
+		require.Same(t, wc.Accounts["acc-uncategorized"], account)
+
+		account, err = wc.AudienceSourceAccount("aud-1")
+		require.NoError(t, err)
+		require.Same(t, wc.Accounts["acc-source"], account)
+	})
+
+	t.Run("stale secret", func(t *testing.T) {
+		account, err := wc.SourceAccount("src-stale")
+		require.Same(t, wc.Accounts["acc-source"], account)
+		var staleErr *modelv2.StaleSecretError
+		require.ErrorAs(t, err, &staleErr)
+		require.Equal(t, 4, staleErr.SecretVersion)
+		require.EqualError(t, err, `account "acc-source" referenced by source "src-stale" (accountId) has secret version 4 instead of 3`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := wc.SourceAccount("src-role")
+		var roleErr *modelv2.AccountRoleError
+		require.ErrorAs(t, err, &roleErr)
+		require.EqualError(t, err, `account "acc-source" referenced by source "src-role" (accountId) has role "singer-facebook-marketing" instead of "bigquery"`)
+
+		_, err = wc.SourceAccount("src-missing")
+		var missingErr *modelv2.MissingAccountError
+		require.ErrorAs(t, err, &missingErr)
+		require.Equal(t, "src-missing", missingErr.Reference.EntityID)
+		require.EqualError(t, err, `account "acc-unknown" referenced by source "src-missing" (accountId) not found`)
+
+		_, err = wc.SourceAccount("src-category")
+		var categoryErr *modelv2.AccountCategoryError
+		require.ErrorAs(t, err, &categoryErr)
+		require.Equal(t, modelv2.AccountCategoryWHT, categoryErr.Category)
+
+		_, err = wc.WHTProjectWarehouseAccount("wht-1")
+		require.ErrorAs(t, err, &categoryErr)
+		require.EqualError(t, err, `account "acc-source" referenced by whtProject "wht-1" (warehouseAccountId) has category "source" instead of "wht"`)
+
+		_, err = wc.SourceAccount("src-none")
+		require.ErrorIs(t, err, modelv2.ErrNoAccount)
+
+		_, err = wc.SourceAccount("src-unknown")
+		require.EqualError(t, err, `source "src-unknown" not found`)
+
+		_, err = wc.AudienceSourceAccount("aud-unknown")
+		require.EqualError(t, err, `audience source "aud-unknown" not found`)
+	})
+
+	t.Run("check all references", func(t *testing.T) {
+		var messages []string
+		for _, err := range wc.CheckAccounts() {
+			messages = append(messages, err.Error())
+		}
+		require.ElementsMatch(t, []string{
+			`account "acc-source" referenced by source "src-stale" (accountId) has secret version 4 instead of 3`,
+			`account "acc-source" referenced by source "src-role" (accountId) has role "singer-facebook-marketing" instead of "bigquery"`,
+			`account "acc-unknown" referenced by source "src-missing" (accountId) not found`,
+			`account "acc-wht" referenced by source "src-category" (accountId) has category "wht" instead of "source"`,
+			`account "acc-source" referenced by whtProject "wht-1" (warehouseAccountId) has category "source" instead of "wht"`,
+		}, messages)
+	})
+}
@@ -9,6 +9,7 @@ type Account struct {
 	UserID        string          `json:"userId"`
 	SecretVersion int             `json:"secretVersion"`
 	Category      AccountCategory `json:"rudderCategory"`
+	Extra         Extra           `json:"-"`
 }
 
 type AccountCategory string
@@ -19,3 +20,10 @@ const (
 	AccountCategoryDataRetention AccountCategory = "dataRetention"
 	AccountCategoryWHT           AccountCategory = "wht"
 )
+
+// AccountSecret is the secret of an account, as returned by the account secret endpoints of the control plane.
+type AccountSecret struct {
+	Secret        map[string]any `json:"secret"`
+	SecretVersion int            `json:"secretVersion"`
+	Extra         Extra          `json:"-"`
+}
@@ -5,4 +5,5 @@ type AudienceSource struct {
 	SourceID  string `json:"sourceId"`
 	AccountID string `json:"accountId"`
 	Deleted   bool   `json:"deleted"`
+	Extra     Extra  `json:"-"`
 }
@@ -0,0 +1,174 @@
+package modelv2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// BucketType is the object storage provider of a [Bucket].
+type BucketType string
+
+const (
+	BucketTypeS3        BucketType = "S3"
+	BucketTypeGCS       BucketType = "GCS"
+	BucketTypeAzureBlob BucketType = "AZURE_BLOB"
+	BucketTypeMinIO     BucketType = "MINIO"
+)
+
+// BucketConfig is the typed config of a [Bucket], one of [*S3BucketConfig], [*GCSBucketConfig],
+// [*AzureBlobBucketConfig] and [*MinIOBucketConfig].
+type BucketConfig interface {
+	// Validate returns a [*BucketConfigError] if fields required to access the bucket are missing.
+	Validate() error
+}
+
+// BucketConfigError is returned when the config of a bucket misses fields required to access it.
+type BucketConfigError struct {
+	Type          BucketType
+	MissingFields []string
+}
+
+func (e *BucketConfigError) Error() string {
+	return fmt.Sprintf("%s bucket config is missing %s", e.Type, strings.Join(e.MissingFields, ", "))
+}
+
+// S3BucketConfig is the config of an AWS S3 bucket. Credentials are either an access key, an IAM role to assume, or
+// none to use the ones of the environment.
+type S3BucketConfig struct {
+	BucketName       string `json:"bucketName"`
+	Prefix           string `json:"prefix"`
+	Region           string `json:"region"`
+	AccessKeyID      string `json:"accessKeyID"`
+	AccessKey        string `json:"accessKey"`
+	RoleBasedAuth    bool   `json:"roleBasedAuth"`
+	IAMRoleARN       string `json:"iamRoleARN"`
+	ExternalID       string `json:"externalID"`
+	EndPoint         string `json:"endPoint"`
+	S3ForcePathStyle bool   `json:"s3ForcePathStyle"`
+	DisableSSL       bool   `json:"disableSSL"`
+	EnableSSE        bool   `json:"enableSSE"`
+}
+
+func (c *S3BucketConfig) Validate() error {
+	return missingFields(BucketTypeS3,
+		requiredField{"bucketName", c.BucketName != ""},
+		requiredField{"iamRoleARN", !c.RoleBasedAuth || c.IAMRoleARN != ""},
+		requiredField{"accessKeyID", c.RoleBasedAuth || c.AccessKey == "" || c.AccessKeyID != ""},
+		requiredField{"accessKey", c.RoleBasedAuth || c.AccessKeyID == "" || c.AccessKey != ""},
+	)
+}
+
+// GCSBucketConfig is the config of a Google Cloud Storage bucket.
+type GCSBucketConfig struct {
+	BucketName string `json:"bucketName"`
+	Prefix     string `json:"prefix"`
+	// Credentials is the JSON key of the service account accessing the bucket.
+	Credentials string `json:"credentials"`
+	EndPoint    string `json:"endPoint"`
+}
+
+func (c *GCSBucketConfig) Validate() error {
+	return missingFields(BucketTypeGCS,
+		requiredField{"bucketName", c.BucketName != ""},
+		requiredField{"credentials", c.Credentials != ""},
+	)
+}
+
+// AzureBlobBucketConfig is the config of an Azure Blob Storage container, accessed either with the key of the
+// storage account or with a SAS token.
+type AzureBlobBucketConfig struct {
+	ContainerName  string `json:"containerName"`
+	Prefix         string `json:"prefix"`
+	AccountName    string `json:"accountName"`
+	AccountKey     string `json:"accountKey"`
+	UseSASTokens   bool   `json:"useSASTokens"`
+	SASToken       string `json:"sasToken"`
+	EndPoint       string `json:"endPoint"`
+	ForcePathStyle bool   `json:"forcePathStyle"`
+	DisableSSL     bool   `json:"disableSSL"`
+}
+
+func (c *AzureBlobBucketConfig) Validate() error {
+	return missingFields(BucketTypeAzureBlob,
+		requiredField{"containerName", c.ContainerName != ""},
+		requiredField{"accountName", c.AccountName != ""},
+		requiredField{"accountKey", c.UseSASTokens || c.AccountKey != ""},
+		requiredField{"sasToken", !c.UseSASTokens || c.SASToken != ""},
+	)
+}
+
+// MinIOBucketConfig is the config of a MinIO bucket.
+type MinIOBucketConfig struct {
+	BucketName      string `json:"bucketName"`
+	Prefix          string `json:"prefix"`
+	EndPoint        string `json:"endPoint"`
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	UseSSL          bool   `json:"useSSL"`
+}
+
+func (c *MinIOBucketConfig) Validate() error {
+	return missingFields(BucketTypeMinIO,
+		requiredField{"bucketName", c.BucketName != ""},
+		requiredField{"endPoint", c.EndPoint != ""},
+		requiredField{"accessKeyID", c.AccessKeyID != ""},
+		requiredField{"secretAccessKey", c.SecretAccessKey != ""},
+	)
+}
+
+// TypedConfig decodes the config of the bucket into the config type of its bucket type, without validating it.
+func (b *Bucket) TypedConfig() (BucketConfig, error) {
+	var config BucketConfig
+	switch b.Type {
+	case BucketTypeS3:
+		config = &S3BucketConfig{}
+	case BucketTypeGCS:
+		config = &GCSBucketConfig{}
+	case BucketTypeAzureBlob:
+		config = &AzureBlobBucketConfig{}
+	case BucketTypeMinIO:
+		config = &MinIOBucketConfig{}
+	default:
+		return nil, fmt.Errorf("unsupported bucket type %q", b.Type)
+	}
+	data, err := jsonrs.Marshal(b.Config)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s bucket config: %w", b.Type, err)
+	}
+	if err := jsonrs.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("decoding %s bucket config: %w", b.Type, err)
+	}
+	return config, nil
+}
+
+// ValidConfig is like [Bucket.TypedConfig], but also validates the config.
+func (b *Bucket) ValidConfig() (BucketConfig, error) {
+	config, err := b.TypedConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+type requiredField struct {
+	name    string
+	present bool
+}
+
+func missingFields(typ BucketType, fields ...requiredField) error {
+	var missing []string
+	for _, f := range fields {
+		if !f.present {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &BucketConfigError{Type: typ, MissingFields: missing}
+}
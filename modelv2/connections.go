@@ -5,4 +5,5 @@ type Connection struct {
 	DestinationID    string `json:"destinationId"`
 	Enabled          bool   `json:"enabled"`
 	ProcessorEnabled bool   `json:"processorEnabled"`
+	Extra            Extra  `json:"-"`
 }
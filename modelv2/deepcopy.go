@@ -0,0 +1,47 @@
+package modelv2
+
+// deepCopyValue returns a deep copy of a value decoded from JSON, i.e. objects (map[string]any) and arrays ([]any)
+// are copied recursively. Any other value is returned as is.
+func deepCopyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return deepCopyMap(v, deepCopyValue)
+	case []any:
+		return deepCopySlice(v, deepCopyValue)
+	default:
+		return v
+	}
+}
+
+// deepCopyMap returns a copy of m, with its values copied by copyValue. A nil map remains nil.
+func deepCopyMap[M ~map[K]V, K comparable, V any](m M, copyValue func(V) V) M {
+	if m == nil {
+		return nil
+	}
+	copied := make(M, len(m))
+	for k, v := range m {
+		copied[k] = copyValue(v)
+	}
+	return copied
+}
+
+// deepCopySlice returns a copy of s, with its elements copied by copyElem. A nil slice remains nil.
+func deepCopySlice[S ~[]E, E any](s S, copyElem func(E) E) S {
+	if s == nil {
+		return nil
+	}
+	copied := make(S, len(s))
+	for i, e := range s {
+		copied[i] = copyElem(e)
+	}
+	return copied
+}
+
+// clonePointer returns a pointer to a copy of the value p points to, or nil if p is nil.
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
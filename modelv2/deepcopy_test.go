@@ -0,0 +1,210 @@
+package modelv2_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestDeepCopy(t *testing.T) {
+	t.Run("workspace configs", func(t *testing.T) {
+		wcs := sampleWorkspaceConfigs(t)
+		copied := wcs.DeepCopy()
+		require.Equal(t, wcs, copied)
+
+		for id, wc := range copied.Workspaces {
+			require.NotSame(t, wcs.Workspaces[id], wc)
+			for sourceID, source := range wc.Sources {
+				require.NotSame(t, wcs.Workspaces[id].Sources[sourceID], source)
+				source.Config["mutated"] = true
+			}
+			for destinationID, destination := range wc.Destinations {
+				require.NotSame(t, wcs.Workspaces[id].Destinations[destinationID], destination)
+				destination.Config["mutated"] = true
+			}
+		}
+		require.NotEqual(t, wcs, copied)
+		require.Equal(t, sampleWorkspaceConfigs(t), wcs, "the original should not be modified")
+	})
+
+	t.Run("nested values", func(t *testing.T) {
+		d := &modelv2.Destination{
+			Config: map[string]any{
+				"object": map[string]any{"key": "value"},
+				"array":  []any{map[string]any{"key": "value"}, "item"},
+			},
+			TransformationIDs: []string{"t-1"},
+			Regions:           []*modelv2.DestinationRegion{{Region: modelv2.RegionEU, Config: map[string]any{"key": "value"}}, nil},
+		}
+		copied := d.DeepCopy()
+		require.Equal(t, d, copied)
+
+		copied.Config["object"].(map[string]any)["key"] = "changed"
+		copied.Config["array"].([]any)[0].(map[string]any)["key"] = "changed"
+		copied.TransformationIDs[0] = "changed"
+		copied.Regions[0].Config["key"] = "changed"
+
+		require.Equal(t, "value", d.Config["object"].(map[string]any)["key"])
+		require.Equal(t, "value", d.Config["array"].([]any)[0].(map[string]any)["key"])
+		require.Equal(t, "t-1", d.TransformationIDs[0])
+		require.Equal(t, "value", d.Regions[0].Config["key"])
+		require.Nil(t, copied.Regions[1])
+	})
+
+	t.Run("nil values", func(t *testing.T) {
+		var wc *modelv2.WorkspaceConfig
+		require.Nil(t, wc.DeepCopy())
+
+		var ws modelv2.Workspaces
+		require.Nil(t, ws.DeepCopy())
+
+		copied := (&modelv2.Source{Name: "source"}).DeepCopy()
+		require.Equal(t, &modelv2.Source{Name: "source"}, copied)
+		require.Nil(t, copied.Config)
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Run("workspace config ignores timestamps", func(t *testing.T) {
+		wc := sampleWorkspaceConfigs(t).Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"]
+		require.NotNil(t, wc)
+		fingerprint, err := wc.Fingerprint()
+		require.NoError(t, err)
+		require.Len(t, fingerprint, 64)
+
+		updated := wc.DeepCopy()
+		updated.UpdatedAt = updated.UpdatedAt.Add(time.Hour)
+		require.NotEmpty(t, updated.Sources)
+		for _, source := range updated.Sources {
+			source.UpdatedAt = source.UpdatedAt.Add(time.Hour)
+			source.CreatedAt = source.CreatedAt.Add(time.Hour)
+		}
+		for _, destination := range updated.Destinations {
+			destination.UpdatedAt = destination.UpdatedAt.Add(time.Hour)
+		}
+		updatedFingerprint, err := updated.Fingerprint()
+		require.NoError(t, err)
+		require.Equal(t, fingerprint, updatedFingerprint)
+		require.Equal(t, wc.UpdatedAt.Add(time.Hour), updated.UpdatedAt, "the workspace config is not modified")
+
+		for _, source := range updated.Sources {
+			source.Enabled = !source.Enabled
+			break
+		}
+		changedFingerprint, err := updated.Fingerprint()
+		require.NoError(t, err)
+		require.NotEqual(t, fingerprint, changedFingerprint)
+	})
+
+	t.Run("entities ignore timestamps", func(t *testing.T) {
+		t1 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		t2 := t1.Add(time.Hour)
+		for _, tc := range []struct {
+			name     string
+			entity   func(updatedAt time.Time) any
+			modified any
+		}{
+			{
+				name: "source",
+				entity: func(updatedAt time.Time) any {
+					return &modelv2.Source{Name: "source", CreatedAt: t1, UpdatedAt: updatedAt}
+				},
+				modified: &modelv2.Source{Name: "renamed", CreatedAt: t1, UpdatedAt: t2},
+			},
+			{
+				name: "destination and its regions",
+				entity: func(updatedAt time.Time) any {
+					return modelv2.Destination{
+						Name:      "destination",
+						UpdatedAt: updatedAt,
+						Regions:   []*modelv2.DestinationRegion{{Region: modelv2.RegionEU, RevisionID: "revision", UpdatedAt: updatedAt}},
+					}
+				},
+				modified: modelv2.Destination{
+					Name:      "destination",
+					UpdatedAt: t2,
+					Regions:   []*modelv2.DestinationRegion{{Region: modelv2.RegionEU, RevisionID: "changed", UpdatedAt: t2}},
+				},
+			},
+			{
+				name: "definitions",
+				entity: func(updatedAt time.Time) any {
+					return modelv2.DestinationDefinitions{"WEBHOOK": {Name: "WEBHOOK", UpdatedAt: updatedAt}}
+				},
+				modified: modelv2.DestinationDefinitions{"WEBHOOK": {Name: "WEBHOOK", DisplayName: "Webhook", UpdatedAt: t2}},
+			},
+			{
+				name: "tracking plans",
+				entity: func(updatedAt time.Time) any {
+					return []*modelv2.DGSourceTrackingPlan{{TrackingPlanID: "tp-1", CreatedAt: t1, UpdatedAt: updatedAt}}
+				},
+				modified: []*modelv2.DGSourceTrackingPlan{{TrackingPlanID: "tp-2", CreatedAt: t1, UpdatedAt: t2}},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				entity := tc.entity(t1)
+				fingerprint, err := modelv2.Fingerprint(entity)
+				require.NoError(t, err)
+
+				bumped, err := modelv2.Fingerprint(tc.entity(t2))
+				require.NoError(t, err)
+				require.Equal(t, fingerprint, bumped)
+				require.Equal(t, tc.entity(t1), entity, "the entity is not modified")
+
+				modified, err := modelv2.Fingerprint(tc.modified)
+				require.NoError(t, err)
+				require.NotEqual(t, fingerprint, modified)
+			})
+		}
+	})
+
+	t.Run("entities with the same content", func(t *testing.T) {
+		a := &modelv2.Destination{Name: "destination", Config: map[string]any{}}
+		b := &modelv2.Destination{Name: "destination", Config: map[string]any{}}
+		for i, key := range []string{"a", "b", "c", "d", "e"} {
+			a.Config[key] = map[string]any{"x": i, "y": []any{key, 1.5}}
+		}
+		for i, key := range []string{"e", "d", "c", "b", "a"} {
+			b.Config[key] = map[string]any{"y": []any{key, 1.5}, "x": 4 - i}
+		}
+		fa, err := modelv2.Fingerprint(a)
+		require.NoError(t, err)
+		fb, err := modelv2.Fingerprint(b)
+		require.NoError(t, err)
+		require.Equal(t, fa, fb)
+
+		b.Config["a"].(map[string]any)["x"] = 1
+		fb, err = modelv2.Fingerprint(b)
+		require.NoError(t, err)
+		require.NotEqual(t, fa, fb)
+	})
+
+	t.Run("nil workspace config", func(t *testing.T) {
+		var wc *modelv2.WorkspaceConfig
+		fingerprint, err := wc.Fingerprint()
+		require.NoError(t, err)
+		expected, err := modelv2.Fingerprint(nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, fingerprint)
+	})
+
+	t.Run("unsupported value", func(t *testing.T) {
+		_, err := modelv2.Fingerprint(map[string]any{"ch": make(chan int)})
+		require.Error(t, err)
+	})
+}
+
+func sampleWorkspaceConfigs(t *testing.T) *modelv2.WorkspaceConfigs {
+	t.Helper()
+	data, err := os.ReadFile("../testdata/sample_namespace.json")
+	require.NoError(t, err)
+	var wcs modelv2.WorkspaceConfigs
+	require.NoError(t, jsonrs.Unmarshal(data, &wcs))
+	return &wcs
+}
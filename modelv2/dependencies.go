@@ -0,0 +1,186 @@
+package modelv2
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// DanglingReference is a reference from an entity of a workspace to another entity that is either missing from the
+// workspace or deleted.
+type DanglingReference struct {
+	// EntityType is the type of the referencing entity, e.g. "audienceSource".
+	EntityType string
+	// EntityID is the ID of the referencing entity, or its position in the list of the workspace for
+	// SQL model version source connections, which have no ID.
+	EntityID string
+	// Field is the JSON name of the field holding the reference, e.g. "accountId".
+	Field string
+	// ReferencedType is the type of the referenced entity, e.g. "account".
+	ReferencedType string
+	ReferencedID   string
+	// Deleted tells whether the referenced entity exists but is deleted.
+	Deleted bool
+}
+
+func (r DanglingReference) String() string {
+	state := "not found"
+	if r.Deleted {
+		state = "deleted"
+	}
+	return fmt.Sprintf("%s %q referenced by %s %q (%s) %s", r.ReferencedType, r.ReferencedID, r.EntityType, r.EntityID, r.Field, state)
+}
+
+// DependencyGraph is the graph of the dependencies of a workspace between SQL model versions, audience sources, the
+// sources they feed and the accounts they use. Deleted entities, and references to missing or deleted entities, are
+// not part of the graph, the latter being reported by [DependencyGraph.DanglingReferences].
+// Lookups return IDs in ascending order, and new slices that can be modified freely.
+type DependencyGraph struct {
+	sqlModelVersionsBySource  map[string][]string
+	sourcesBySQLModelVersion  map[string][]string
+	sqlModelVersionsByAccount map[string][]string
+	audienceSourcesBySource   map[string][]*AudienceSource
+	audienceSourcesByAccount  map[string][]*AudienceSource
+	dangling                  []DanglingReference
+}
+
+// DependencyGraph builds the dependency graph of the workspace. The graph references the entities of the workspace,
+// thus it has to be built again whenever they change.
+func (wc *WorkspaceConfig) DependencyGraph() *DependencyGraph {
+	g := &DependencyGraph{
+		sqlModelVersionsBySource:  make(map[string][]string),
+		sourcesBySQLModelVersion:  make(map[string][]string),
+		sqlModelVersionsByAccount: make(map[string][]string),
+		audienceSourcesBySource:   make(map[string][]*AudienceSource),
+		audienceSourcesByAccount:  make(map[string][]*AudienceSource),
+	}
+
+	for _, versionID := range slices.Sorted(maps.Keys(wc.SQLModelVersions)) {
+		version := wc.SQLModelVersions[versionID]
+		if version == nil || version.AccountID == "" {
+			continue
+		}
+		ref := DanglingReference{EntityType: "sqlModelVersion", EntityID: versionID, Field: "accountId"}
+		if g.checkAccount(wc, ref, version.AccountID) {
+			g.sqlModelVersionsByAccount[version.AccountID] = append(g.sqlModelVersionsByAccount[version.AccountID], versionID)
+		}
+	}
+
+	for i, conn := range wc.SQLModelVersionSourceConnections {
+		if conn == nil {
+			continue
+		}
+		entityID := strconv.Itoa(i)
+		versionOK := true
+		if version := wc.SQLModelVersions[conn.SQLModelVersionID]; version == nil {
+			versionOK = false
+			g.dangling = append(g.dangling, DanglingReference{
+				EntityType:     "sqlModelVersionSourceConnection",
+				EntityID:       entityID,
+				Field:          "sqlModelVersionId",
+				ReferencedType: "sqlModelVersion",
+				ReferencedID:   conn.SQLModelVersionID,
+			})
+		}
+		ref := DanglingReference{EntityType: "sqlModelVersionSourceConnection", EntityID: entityID, Field: "sourceId"}
+		if g.checkSource(wc, ref, conn.SourceID) && versionOK {
+			g.sqlModelVersionsBySource[conn.SourceID] = appendUnique(g.sqlModelVersionsBySource[conn.SourceID], conn.SQLModelVersionID)
+			g.sourcesBySQLModelVersion[conn.SQLModelVersionID] = appendUnique(g.sourcesBySQLModelVersion[conn.SQLModelVersionID], conn.SourceID)
+		}
+	}
+
+	for _, as := range wc.AudienceSources {
+		if as == nil || as.Deleted {
+			continue
+		}
+		ref := DanglingReference{EntityType: "audienceSource", EntityID: as.ID, Field: "sourceId"}
+		if as.SourceID != "" && g.checkSource(wc, ref, as.SourceID) {
+			g.audienceSourcesBySource[as.SourceID] = append(g.audienceSourcesBySource[as.SourceID], as)
+		}
+		ref.Field = "accountId"
+		if as.AccountID != "" && g.checkAccount(wc, ref, as.AccountID) {
+			g.audienceSourcesByAccount[as.AccountID] = append(g.audienceSourcesByAccount[as.AccountID], as)
+		}
+	}
+
+	for _, ids := range g.sqlModelVersionsBySource {
+		slices.Sort(ids)
+	}
+	for _, ids := range g.sourcesBySQLModelVersion {
+		slices.Sort(ids)
+	}
+	for _, list := range g.audienceSourcesBySource {
+		sortAudienceSources(list)
+	}
+	for _, list := range g.audienceSourcesByAccount {
+		sortAudienceSources(list)
+	}
+	return g
+}
+
+// SQLModelVersionsFeeding returns the IDs of the SQL model versions connected to a source.
+func (g *DependencyGraph) SQLModelVersionsFeeding(sourceID string) []string {
+	return slices.Clone(g.sqlModelVersionsBySource[sourceID])
+}
+
+// SourcesFedBy returns the IDs of the sources a SQL model version is connected to.
+func (g *DependencyGraph) SourcesFedBy(sqlModelVersionID string) []string {
+	return slices.Clone(g.sourcesBySQLModelVersion[sqlModelVersionID])
+}
+
+// SQLModelVersionsUsingAccount returns the IDs of the SQL model versions running with an account.
+func (g *DependencyGraph) SQLModelVersionsUsingAccount(accountID string) []string {
+	return slices.Clone(g.sqlModelVersionsByAccount[accountID])
+}
+
+// AudienceSourcesFeeding returns the audience sources of a source, ordered by ID.
+func (g *DependencyGraph) AudienceSourcesFeeding(sourceID string) []*AudienceSource {
+	return slices.Clone(g.audienceSourcesBySource[sourceID])
+}
+
+// AudienceSourcesUsingAccount returns the audience sources using an account, ordered by ID.
+func (g *DependencyGraph) AudienceSourcesUsingAccount(accountID string) []*AudienceSource {
+	return slices.Clone(g.audienceSourcesByAccount[accountID])
+}
+
+// DanglingReferences returns the references of the entities of the workspace to missing or deleted sources, SQL
+// model versions and accounts. References of deleted entities are not checked.
+func (g *DependencyGraph) DanglingReferences() []DanglingReference {
+	return slices.Clone(g.dangling)
+}
+
+// checkSource tells whether the source referenced by ref exists and is not deleted, recording a dangling reference
+// otherwise.
+func (g *DependencyGraph) checkSource(wc *WorkspaceConfig, ref DanglingReference, sourceID string) bool {
+	ref.ReferencedType, ref.ReferencedID = "source", sourceID
+	source := wc.Sources[sourceID]
+	if source != nil && !source.Deleted {
+		return true
+	}
+	ref.Deleted = source != nil
+	g.dangling = append(g.dangling, ref)
+	return false
+}
+
+// checkAccount tells whether the account referenced by ref exists, recording a dangling reference otherwise.
+func (g *DependencyGraph) checkAccount(wc *WorkspaceConfig, ref DanglingReference, accountID string) bool {
+	ref.ReferencedType, ref.ReferencedID = "account", accountID
+	if wc.Accounts[accountID] != nil {
+		return true
+	}
+	g.dangling = append(g.dangling, ref)
+	return false
+}
+
+func appendUnique(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+func sortAudienceSources(list []*AudienceSource) {
+	slices.SortFunc(list, func(a, b *AudienceSource) int { return cmp.Compare(a.ID, b.ID) })
+}
@@ -0,0 +1,95 @@
+package modelv2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	wc := &modelv2.WorkspaceConfig{
+		Sources: map[string]*modelv2.Source{
+			"source-1": {Name: "source 1"},
+			"source-2": {Name: "source 2"},
+			"source-3": {Name: "source 3", Deleted: true},
+		},
+		Accounts: map[string]*modelv2.Account{
+			"account-1": {Name: "account 1"},
+			"account-2": {Name: "account 2"},
+		},
+		SQLModelVersions: map[string]*modelv2.SQLModelVersion{
+			"version-1": {SQLModelID: "model-1", AccountID: "account-1"},
+			"version-2": {SQLModelID: "model-1", AccountID: "account-1"},
+			"version-3": {SQLModelID: "model-2", AccountID: "account-3"},
+		},
+		SQLModelVersionSourceConnections: []*modelv2.SQLModelVersionSourceConnection{
+			{SourceID: "source-1", SQLModelVersionID: "version-2"},
+			{SourceID: "source-1", SQLModelVersionID: "version-1"},
+			{SourceID: "source-2", SQLModelVersionID: "version-1"},
+			{SourceID: "source-2", SQLModelVersionID: "version-1"},
+			{SourceID: "source-3", SQLModelVersionID: "version-2"},
+			{SourceID: "source-2", SQLModelVersionID: "version-4"},
+			nil,
+		},
+		AudienceSources: []*modelv2.AudienceSource{
+			{ID: "audience-2", SourceID: "source-1", AccountID: "account-2"},
+			{ID: "audience-1", SourceID: "source-1", AccountID: "account-2"},
+			{ID: "audience-3", SourceID: "source-4", AccountID: "account-1"},
+			{ID: "audience-4", SourceID: "source-5", AccountID: "account-4", Deleted: true},
+		},
+	}
+	g := wc.DependencyGraph()
+
+	t.Run("sql model versions", func(t *testing.T) {
+		require.Equal(t, []string{"version-1", "version-2"}, g.SQLModelVersionsFeeding("source-1"))
+		require.Equal(t, []string{"version-1"}, g.SQLModelVersionsFeeding("source-2"))
+		require.Empty(t, g.SQLModelVersionsFeeding("source-3"), "deleted sources are excluded")
+		require.Equal(t, []string{"source-1", "source-2"}, g.SourcesFedBy("version-1"))
+		require.Equal(t, []string{"source-1"}, g.SourcesFedBy("version-2"))
+		require.Equal(t, []string{"version-1", "version-2"}, g.SQLModelVersionsUsingAccount("account-1"))
+		require.Empty(t, g.SQLModelVersionsUsingAccount("account-3"))
+	})
+
+	t.Run("audience sources", func(t *testing.T) {
+		audienceSources := g.AudienceSourcesUsingAccount("account-2")
+		require.Len(t, audienceSources, 2)
+		require.Equal(t, "audience-1", audienceSources[0].ID)
+		require.Equal(t, "audience-2", audienceSources[1].ID)
+		require.Equal(t, audienceSources, g.AudienceSourcesFeeding("source-1"))
+
+		audienceSources = g.AudienceSourcesUsingAccount("account-1")
+		require.Len(t, audienceSources, 1)
+		require.Same(t, wc.AudienceSources[2], audienceSources[0])
+		require.Empty(t, g.AudienceSourcesFeeding("source-4"))
+		require.Empty(t, g.AudienceSourcesUsingAccount("account-4"), "deleted audience sources are excluded")
+	})
+
+	t.Run("dangling references", func(t *testing.T) {
+		require.ElementsMatch(t, []modelv2.DanglingReference{
+			{EntityType: "sqlModelVersion", EntityID: "version-3", Field: "accountId", ReferencedType: "account", ReferencedID: "account-3"},
+			{EntityType: "sqlModelVersionSourceConnection", EntityID: "4", Field: "sourceId", ReferencedType: "source", ReferencedID: "source-3", Deleted: true},
+			{EntityType: "sqlModelVersionSourceConnection", EntityID: "5", Field: "sqlModelVersionId", ReferencedType: "sqlModelVersion", ReferencedID: "version-4"},
+			{EntityType: "audienceSource", EntityID: "audience-3", Field: "sourceId", ReferencedType: "source", ReferencedID: "source-4"},
+		}, g.DanglingReferences())
+		require.Equal(t,
+			`source "source-3" referenced by sqlModelVersionSourceConnection "4" (sourceId) deleted`,
+			modelv2.DanglingReference{EntityType: "sqlModelVersionSourceConnection", EntityID: "4", Field: "sourceId", ReferencedType: "source", ReferencedID: "source-3", Deleted: true}.String(),
+		)
+	})
+
+	t.Run("results can be modified", func(t *testing.T) {
+		g.SQLModelVersionsFeeding("source-1")[0] = "changed"
+		require.Equal(t, []string{"version-1", "version-2"}, g.SQLModelVersionsFeeding("source-1"))
+	})
+
+	t.Run("sample workspace", func(t *testing.T) {
+		for _, wc := range sampleWorkspaceConfigs(t).Workspaces {
+			if wc == nil {
+				continue
+			}
+			require.NotNil(t, wc.DependencyGraph())
+		}
+	})
+}
@@ -1,19 +1,35 @@
 package modelv2
 
+import (
+	"maps"
+	"time"
+)
+
 type Destination struct {
-	Name              string               `json:"name"`
-	Enabled           bool                 `json:"enabled"`
-	Config            map[string]any       `json:"config"`
-	DefinitionName    string               `json:"destinationDefinitionName"`
-	Deleted           bool                 `json:"deleted"`
-	TransformationIDs []string             `json:"transformationIds"`
-	RevisionID        string               `json:"revisionId"`
-	SecretVersion     int                  `json:"secretVersion"`
-	Regions           []*DestinationRegion `json:"regions"`
-	// TODO: consider adding the following fields
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
-	// LiveEventsConfig ...
+	Name              string                       `json:"name"`
+	Enabled           bool                         `json:"enabled"`
+	Config            map[string]any               `json:"config"`
+	DefinitionName    string                       `json:"destinationDefinitionName"`
+	Deleted           bool                         `json:"deleted"`
+	TransformationIDs []string                     `json:"transformationIds"`
+	RevisionID        string                       `json:"revisionId"`
+	SecretVersion     int                          `json:"secretVersion"`
+	Regions           []*DestinationRegion         `json:"regions"`
+	CreatedAt         time.Time                    `json:"createdAt"`
+	UpdatedAt         time.Time                    `json:"updatedAt"`
+	LiveEventsConfig  *DestinationLiveEventsConfig `json:"liveEventsConfig"`
+	Extra             Extra                        `json:"-"`
+}
+
+func (d *Destination) GetUpdatedAt() time.Time { return d.UpdatedAt }
+
+// DestinationLiveEventsConfig is the live events configuration of a destination, i.e. whether the events delivered
+// to it are uploaded to be shown as live events.
+type DestinationLiveEventsConfig struct {
+	EventDelivery bool `json:"eventDelivery"`
+	// EventDeliveryTS is the time (in milliseconds since the Unix epoch) when EventDelivery was last changed.
+	EventDeliveryTS int64 `json:"eventDeliveryTS"`
+	Extra           Extra `json:"-"`
 }
 
 type DestinationRegion struct {
@@ -21,19 +37,80 @@ type DestinationRegion struct {
 	Config        map[string]any `json:"config"`
 	RevisionID    string         `json:"revisionId"`
 	SecretVersion int            `json:"secretVersion"`
-	// TODO: consider adding the following fields
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	Extra         Extra          `json:"-"`
 }
 
+func (r *DestinationRegion) GetUpdatedAt() time.Time { return r.UpdatedAt }
+
 type DestinationDefinition struct {
 	Name        string         `json:"name"`
 	DisplayName string         `json:"displayName"`
 	Category    string         `json:"category"`
 	Options     map[string]any `json:"options"`
 	Config      map[string]any `json:"config"`
-	// TODO: consider adding the following fields
-	// ResponseRules map[string]interface{} `json:"responseRules"`
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
+	// ResponseRules are the rules used to classify the responses of the destination, e.g. as retryable or aborted.
+	ResponseRules map[string]any `json:"responseRules"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	Extra         Extra          `json:"-"`
+}
+
+func (dd *DestinationDefinition) GetUpdatedAt() time.Time { return dd.UpdatedAt }
+
+// Effective returns the destination as seen from a region, i.e. with the overrides of the matching DestinationRegion
+// applied to it. If the destination has no overrides for the region, a copy of it is returned.
+//
+// Overrides are applied as follows:
+//   - Config is deep merged: objects are merged recursively, while any other value (including arrays) in the
+//     override replaces the base one. A null value in the override removes the field.
+//   - RevisionID and SecretVersion are replaced, unless they are empty (zero) in the override.
+//   - UpdatedAt is the latest of the two, so that updating the overrides of a region updates the destination too.
+//
+// The returned destination has no Regions. It shares the values that weren't overridden with d, thus neither should
+// be modified.
+func (d *Destination) Effective(region Region) *Destination {
+	effective := *d
+	effective.Regions = nil
+	for _, r := range d.Regions {
+		if r == nil || r.Region != region {
+			continue
+		}
+		effective.Config = mergeConfig(d.Config, r.Config)
+		if r.RevisionID != "" {
+			effective.RevisionID = r.RevisionID
+		}
+		if r.SecretVersion != 0 {
+			effective.SecretVersion = r.SecretVersion
+		}
+		if r.UpdatedAt.After(effective.UpdatedAt) {
+			effective.UpdatedAt = r.UpdatedAt
+		}
+		break
+	}
+	return &effective
+}
+
+// mergeConfig deep merges override into base, without modifying either of them.
+func mergeConfig(base, override map[string]any) map[string]any {
+	if override == nil {
+		return base
+	}
+	merged := make(map[string]any, len(base)+len(override))
+	maps.Copy(merged, base)
+	for key, value := range override {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		baseObject, baseOk := merged[key].(map[string]any)
+		overrideObject, overrideOk := value.(map[string]any)
+		if baseOk && overrideOk {
+			merged[key] = mergeConfig(baseObject, overrideObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
 }
@@ -0,0 +1,108 @@
+package modelv2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestEntityTimestamps(t *testing.T) {
+	wcs := sampleWorkspaceConfigs(t)
+
+	t.Run("golden", func(t *testing.T) {
+		sources := make(map[string]*modelv2.Source)
+		destinations := make(map[string]*modelv2.Destination)
+		transformations := make(map[string]*modelv2.Transformation)
+		for _, wc := range wcs.Workspaces {
+			if wc == nil {
+				continue
+			}
+			for id, s := range wc.Sources {
+				sources[id] = &modelv2.Source{
+					CreatedBy:        s.CreatedBy,
+					CreatedAt:        s.CreatedAt,
+					UpdatedAt:        s.UpdatedAt,
+					LiveEventsConfig: s.LiveEventsConfig,
+					GeoEnrichment:    s.GeoEnrichment,
+				}
+			}
+			for id, d := range wc.Destinations {
+				destinations[id] = &modelv2.Destination{
+					CreatedAt:        d.CreatedAt,
+					UpdatedAt:        d.UpdatedAt,
+					LiveEventsConfig: d.LiveEventsConfig,
+				}
+			}
+			for id, tr := range wc.Transformations {
+				transformations[id] = &modelv2.Transformation{LiveEventsConfig: tr.LiveEventsConfig}
+			}
+		}
+		sourceDefinitions := make(modelv2.SourceDefinitions)
+		for name, sd := range wcs.SourceDefinitions {
+			sourceDefinitions[name] = &modelv2.SourceDefinition{CreatedAt: sd.CreatedAt, UpdatedAt: sd.UpdatedAt}
+		}
+		destinationDefinitions := make(modelv2.DestinationDefinitions)
+		for name, dd := range wcs.DestinationDefinitions {
+			destinationDefinitions[name] = &modelv2.DestinationDefinition{
+				ResponseRules: dd.ResponseRules,
+				CreatedAt:     dd.CreatedAt,
+				UpdatedAt:     dd.UpdatedAt,
+			}
+		}
+
+		require.Equal(t, goldenSources, sources)
+		require.Equal(t, goldenDestinations, destinations)
+		require.Equal(t, goldenTransformations, transformations)
+		require.Equal(t, goldenSourceDefinitions, sourceDefinitions)
+		require.Equal(t, goldenDestinationDefinitions, destinationDefinitions)
+	})
+
+	t.Run("modeled fields are not extra", func(t *testing.T) {
+		for _, wc := range wcs.Workspaces {
+			if wc == nil {
+				continue
+			}
+			for _, s := range wc.Sources {
+				for _, name := range []string{"createdBy", "createdAt", "updatedAt", "liveEventsConfig", "geoEnrichment"} {
+					require.NotContains(t, s.Extra, name)
+				}
+			}
+			for _, d := range wc.Destinations {
+				for _, name := range []string{"createdAt", "updatedAt", "liveEventsConfig"} {
+					require.NotContains(t, d.Extra, name)
+				}
+			}
+		}
+		for _, dd := range wcs.DestinationDefinitions {
+			require.NotContains(t, dd.Extra, "responseRules")
+		}
+	})
+
+	t.Run("entity level changes", func(t *testing.T) {
+		sources := wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Sources
+		updated := make(map[string]*modelv2.Source, len(sources))
+		for id, s := range sources {
+			updated[id] = s.DeepCopy()
+		}
+		require.True(t, diff.CompareByUpdatedAt(sources, updated).IsEmpty())
+
+		updated["2hCDcJJGtZCIMDWjAyjsOg0W1Hr"].UpdatedAt = updated["2hCDcJJGtZCIMDWjAyjsOg0W1Hr"].UpdatedAt.Add(time.Second)
+		require.Equal(t, diff.Changes[string]{Changed: []string{"2hCDcJJGtZCIMDWjAyjsOg0W1Hr"}}, diff.CompareByUpdatedAt(sources, updated))
+	})
+
+	t.Run("effective destination", func(t *testing.T) {
+		d := &modelv2.Destination{
+			UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Regions: []*modelv2.DestinationRegion{
+				{Region: modelv2.RegionEU, UpdatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+				{Region: modelv2.RegionUS, UpdatedAt: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		}
+		require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), d.Effective(modelv2.RegionEU).GetUpdatedAt())
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), d.Effective(modelv2.RegionUS).GetUpdatedAt())
+	})
+}
@@ -0,0 +1,254 @@
+package modelv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// Extra holds the JSON fields of an entity that are not modeled by its type (e.g. fields recently added to the
+// control plane), keyed by their name. They are kept when decoding an entity and emitted again when encoding it, so
+// that no field is lost along the way.
+type Extra map[string]json.RawMessage
+
+// Decode decodes the value of the extra field with the given name into v. It returns false if there is no such field.
+func (e Extra) Decode(name string, v any) (bool, error) {
+	raw, ok := e[name]
+	if !ok {
+		return false, nil
+	}
+	if err := jsonrs.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("decoding extra field %q: %w", name, err)
+	}
+	return true, nil
+}
+
+// Redacted returns a copy of the extra fields with the values of secret looking fields (see [IsSecretKey]) replaced
+// by [RedactedValue], and the other values redacted with [RedactJSON].
+func (e Extra) Redacted() Extra {
+	if e == nil {
+		return nil
+	}
+	redacted := make(Extra, len(e))
+	for name, raw := range e {
+		if IsSecretKey(name) && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			redacted[name] = json.RawMessage(`"` + RedactedValue + `"`)
+			continue
+		}
+		redacted[name] = RedactJSON(raw)
+	}
+	return redacted
+}
+
+// knownFields caches the JSON fields of the types decoded by unmarshalWithExtra.
+var knownFields sync.Map // reflect.Type -> *jsonFieldNames
+
+// jsonFieldNames holds the JSON names of the fields of a struct type, both as they are and lowercased, since JSON
+// fields are matched case-insensitively when decoding.
+type jsonFieldNames struct {
+	exact  map[string]struct{}
+	folded map[string]struct{}
+}
+
+func (f *jsonFieldNames) has(name string) bool {
+	if _, ok := f.exact[name]; ok {
+		return true
+	}
+	_, ok := f.folded[strings.ToLower(name)]
+	return ok
+}
+
+// hasBytes is like has, sparing the conversion of exact names to strings.
+func (f *jsonFieldNames) hasBytes(name []byte) bool {
+	if _, ok := f.exact[string(name)]; ok {
+		return true
+	}
+	_, ok := f.folded[string(bytes.ToLower(name))]
+	return ok
+}
+
+// jsonFields returns the JSON names of the fields of a struct type.
+func jsonFields(t reflect.Type) *jsonFieldNames {
+	if fields, ok := knownFields.Load(t); ok {
+		return fields.(*jsonFieldNames)
+	}
+	fields := &jsonFieldNames{
+		exact:  make(map[string]struct{}, t.NumField()),
+		folded: make(map[string]struct{}, t.NumField()),
+	}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = sf.Name
+		}
+		fields.exact[name] = struct{}{}
+		fields.folded[strings.ToLower(name)] = struct{}{}
+	}
+	knownFields.Store(t, fields)
+	return fields
+}
+
+// unmarshalWithExtra decodes data into v, storing the (compacted) fields of data that are not fields of T into extra.
+// Once v is decoded, data is known to be a valid JSON object, thus its fields are only scanned for unknown names
+// rather than decoded a second time, which would be repeated at every level of nested types.
+func unmarshalWithExtra[T any](data []byte, v *T, extra *Extra) error {
+	if err := jsonrs.Unmarshal(data, v); err != nil {
+		return err
+	}
+	*extra = nil
+	known := jsonFields(reflect.TypeFor[T]())
+	return scanObject(data, func(quoted, raw []byte) error {
+		// names are only decoded if they are unknown or hold escape sequences
+		if bytes.IndexByte(quoted, '\\') < 0 && known.hasBytes(quoted[1:len(quoted)-1]) {
+			return nil
+		}
+		var name string
+		if err := jsonrs.Unmarshal(quoted, &name); err != nil {
+			return err
+		}
+		if known.has(name) {
+			return nil
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, raw); err != nil {
+			return err
+		}
+		if *extra == nil {
+			*extra = make(Extra)
+		}
+		(*extra)[name] = compacted.Bytes()
+		return nil
+	})
+}
+
+// scanObject calls fn with the quoted name and the raw value of every field of data, which must be valid JSON.
+// Nothing is done if data is not an object, e.g. null.
+func scanObject(data []byte, fn func(quoted, raw []byte) error) error {
+	i := skipSpaces(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return nil
+	}
+	for i = skipSpaces(data, i+1); i < len(data) && data[i] != '}'; {
+		end := skipString(data, i)
+		quoted := data[i:end]
+		start := skipSpaces(data, skipSpaces(data, end)+1) // skip the colon
+		end = skipValue(data, start)
+		if err := fn(quoted, data[start:end]); err != nil {
+			return err
+		}
+		if i = skipSpaces(data, end); i < len(data) && data[i] == ',' {
+			i = skipSpaces(data, i+1)
+		}
+	}
+	return nil
+}
+
+func skipSpaces(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipString returns the index following the string starting at i.
+func skipString(data []byte, i int) int {
+	for i++; ; i++ {
+		n := bytes.IndexByte(data[i:], '"')
+		if n < 0 {
+			return len(data)
+		}
+		i += n
+		// the quote is escaped if it follows an odd number of backslashes
+		escapes := 0
+		for j := i - 1; data[j] == '\\'; j-- {
+			escapes++
+		}
+		if escapes%2 == 0 {
+			return i + 1
+		}
+	}
+}
+
+// skipValue returns the index following the value starting at i.
+func skipValue(data []byte, i int) int {
+	if i == len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default: // number, true, false or null
+		for i < len(data) && !strings.ContainsRune(",}] \t\n\r", rune(data[i])) {
+			i++
+		}
+		return i
+	}
+}
+
+// marshalWithExtra encodes v, adding the fields of extra that are not fields of T, sorted by name.
+func marshalWithExtra[T any](v T, extra Extra) ([]byte, error) {
+	data, err := jsonrs.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[len(data)-1] != '}' {
+		return nil, fmt.Errorf("encoding %T: expected a JSON object", v)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+64*len(extra)))
+	buf.Write(data[:len(data)-1])
+	empty := len(bytes.TrimSpace(data[1:len(data)-1])) == 0
+	known := jsonFields(reflect.TypeFor[T]())
+	for _, name := range slices.Sorted(maps.Keys(extra)) {
+		if known.has(name) {
+			continue
+		}
+		key, err := jsonrs.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := jsonrs.Marshal(extra[name])
+		if err != nil {
+			return nil, fmt.Errorf("encoding extra field %q: %w", name, err)
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
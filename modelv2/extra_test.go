@@ -0,0 +1,233 @@
+package modelv2_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestExtra(t *testing.T) {
+	t.Run("all types keep unknown fields", func(t *testing.T) {
+		seen := make(map[reflect.Type]bool)
+		var check func(typ reflect.Type)
+		check = func(typ reflect.Type) {
+			for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Map || typ.Kind() == reflect.Slice {
+				typ = typ.Elem()
+			}
+			if typ.Kind() != reflect.Struct || typ.PkgPath() != reflect.TypeFor[modelv2.Extra]().PkgPath() || seen[typ] {
+				return
+			}
+			seen[typ] = true
+			sf, ok := typ.FieldByName("Extra")
+			require.True(t, ok, "%s should have an Extra field", typ)
+			require.Equal(t, reflect.TypeFor[modelv2.Extra](), sf.Type, typ.String())
+			require.True(t, typ.Implements(reflect.TypeFor[json.Marshaler]()), "%s should implement json.Marshaler", typ)
+			require.True(t, reflect.PointerTo(typ).Implements(reflect.TypeFor[json.Unmarshaler]()), "%s should implement json.Unmarshaler", typ)
+			for i := range typ.NumField() {
+				check(typ.Field(i).Type)
+			}
+		}
+		check(reflect.TypeFor[modelv2.WorkspaceConfigs]())
+		check(reflect.TypeFor[modelv2.AccountSecret]())
+		require.Greater(t, len(seen), 20)
+	})
+
+	t.Run("round trip is lossless", func(t *testing.T) {
+		data, err := os.ReadFile("../testdata/sample_namespace.json")
+		require.NoError(t, err)
+		wcs := sampleWorkspaceConfigs(t)
+
+		encoded, err := jsonrs.Marshal(wcs)
+		require.NoError(t, err)
+		var expected, actual any
+		require.NoError(t, jsonrs.Unmarshal(data, &expected))
+		require.NoError(t, jsonrs.Unmarshal(encoded, &actual))
+		requireJSONSubset(t, expected, actual, "$")
+
+		var decoded modelv2.WorkspaceConfigs
+		require.NoError(t, jsonrs.Unmarshal(encoded, &decoded))
+		require.Equal(t, wcs, &decoded)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		settings := sampleWorkspaceConfigs(t).Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"].Settings
+		require.NotNil(t, settings)
+		require.NotContains(t, settings.Extra, "dataRetention")
+
+		var eventAuditEnabled bool
+		ok, err := settings.Extra.Decode("eventAuditEnabled", &eventAuditEnabled)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, eventAuditEnabled)
+
+		ok, err = settings.Extra.Decode("missing", &eventAuditEnabled)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		var wrongType int
+		ok, err = settings.Extra.Decode("eventAuditEnabled", &wrongType)
+		require.True(t, ok)
+		require.ErrorContains(t, err, `decoding extra field "eventAuditEnabled"`)
+	})
+
+	t.Run("fields are matched case-insensitively", func(t *testing.T) {
+		var connection modelv2.Connection
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{"SourceID":"source-1","destinationId":"destination-1","foo":1}`), &connection))
+		require.Equal(t, modelv2.Connection{
+			SourceID:      "source-1",
+			DestinationID: "destination-1",
+			Extra:         modelv2.Extra{"foo": json.RawMessage(`1`)},
+		}, connection)
+	})
+
+	t.Run("unknown fields next to tricky values", func(t *testing.T) {
+		var connection modelv2.Connection
+		require.NoError(t, jsonrs.Unmarshal([]byte(` {
+			"sourceId" : "source-\\\"1}",
+			"nested": {"a": ["]", "}", {"b": "\\"}], "c": null} ,
+			"number":-1.5e3,"bool" :true,
+			"\u0064estinationId": "destination-1",
+			"esc\"aped": "x"
+		} `), &connection))
+		require.Equal(t, modelv2.Connection{
+			SourceID:      `source-\"1}`,
+			DestinationID: "destination-1",
+			Extra: modelv2.Extra{
+				"nested":   json.RawMessage(`{"a":["]","}",{"b":"\\"}],"c":null}`),
+				"number":   json.RawMessage(`-1.5e3`),
+				"bool":     json.RawMessage(`true`),
+				`esc"aped`: json.RawMessage(`"x"`),
+			},
+		}, connection)
+
+		connection = modelv2.Connection{Extra: modelv2.Extra{"stale": json.RawMessage(`1`)}}
+		require.NoError(t, jsonrs.Unmarshal([]byte(`null`), &connection))
+		require.Nil(t, connection.Extra)
+	})
+
+	t.Run("no unknown fields", func(t *testing.T) {
+		var connection modelv2.Connection
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{"sourceId":"source-1"}`), &connection))
+		require.Nil(t, connection.Extra)
+	})
+
+	t.Run("encoding", func(t *testing.T) {
+		encoded, err := jsonrs.Marshal(modelv2.Connection{
+			SourceID: "source-1",
+			Extra: modelv2.Extra{
+				"b":        json.RawMessage(`{ "x": [1, 2] }`),
+				"a":        json.RawMessage(`"a"`),
+				"sourceId": json.RawMessage(`"ignored"`),
+				"nil":      nil,
+			},
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"sourceId": "source-1",
+			"destinationId": "",
+			"enabled": false,
+			"processorEnabled": false,
+			"a": "a",
+			"b": {"x": [1, 2]},
+			"nil": null
+		}`, string(encoded))
+
+		_, err = jsonrs.Marshal(&modelv2.Connection{Extra: modelv2.Extra{"invalid": json.RawMessage(`{`)}})
+		require.Error(t, err)
+	})
+
+	t.Run("redaction", func(t *testing.T) {
+		extra := modelv2.Extra{
+			"credentials": json.RawMessage(`{"user":"user","pass":"pass"}`),
+			"options":     json.RawMessage(`{"apiKey":"some-api-key","region":"EU"}`),
+			"token":       json.RawMessage(`null`),
+		}
+		redacted := extra.Redacted()
+		require.JSONEq(t, `"`+modelv2.RedactedValue+`"`, string(redacted["credentials"]))
+		require.JSONEq(t, `{"apiKey":"`+modelv2.RedactedValue+`","region":"EU"}`, string(redacted["options"]))
+		require.JSONEq(t, `null`, string(redacted["token"]))
+		require.JSONEq(t, `{"apiKey":"some-api-key","region":"EU"}`, string(extra["options"]), "the original should not be modified")
+
+		source := &modelv2.Source{Extra: extra}
+		require.Equal(t, redacted, source.Redacted().Extra)
+	})
+
+	t.Run("deep copy", func(t *testing.T) {
+		source := &modelv2.Source{Extra: modelv2.Extra{"foo": json.RawMessage(`"bar"`)}}
+		copied := source.DeepCopy()
+		require.Equal(t, source, copied)
+		copied.Extra["foo"][1] = 'B'
+		require.JSONEq(t, `"bar"`, string(source.Extra["foo"]))
+	})
+}
+
+// BenchmarkDecodeSample measures the cost of decoding the sample workspace configs, keeping the fields they don't
+// model in Extra, against decoding them as generic JSON.
+func BenchmarkDecodeSample(b *testing.B) {
+	data, err := os.ReadFile("../testdata/sample_namespace.json")
+	require.NoError(b, err)
+
+	b.Run("WorkspaceConfigs", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for b.Loop() {
+			var wcs modelv2.WorkspaceConfigs
+			if err := jsonrs.Unmarshal(data, &wcs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("any", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for b.Loop() {
+			var v any
+			if err := jsonrs.Unmarshal(data, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// requireJSONSubset requires all the values of expected to be in actual, which can have additional object fields.
+// Null values of expected can have any value in actual, since null modeled fields are decoded as zero values, and
+// timestamps only need to be equal, not to have the same format.
+func requireJSONSubset(t *testing.T, expected, actual any, path string) {
+	t.Helper()
+	switch expected := expected.(type) {
+	case nil:
+	case map[string]any:
+		actual, ok := actual.(map[string]any)
+		require.True(t, ok, "%s should be an object", path)
+		for key, value := range expected {
+			require.Contains(t, actual, key, "%s should have field %q", path, key)
+			requireJSONSubset(t, value, actual[key], path+"."+key)
+		}
+	case []any:
+		actual, ok := actual.([]any)
+		require.True(t, ok, "%s should be an array", path)
+		require.Len(t, actual, len(expected), path)
+		for i := range expected {
+			requireJSONSubset(t, expected[i], actual[i], path)
+		}
+	case string:
+		if expectedTime, err := time.Parse(time.RFC3339Nano, expected); err == nil {
+			actualTime, err := time.Parse(time.RFC3339Nano, actual.(string))
+			require.NoError(t, err, path)
+			require.True(t, expectedTime.Equal(actualTime), "%s: expected %s, got %s", path, expectedTime, actualTime)
+			return
+		}
+		require.Equal(t, expected, actual, path)
+	default:
+		require.Equal(t, expected, actual, path)
+	}
+}
@@ -0,0 +1,176 @@
+package modelv2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// Fingerprint returns a stable hash of the content of v, e.g. a workspace config or one of its entities, ignoring the
+// UpdatedAt and CreatedAt of all the structs it holds, so that only real changes of its content result in a different
+// fingerprint.
+//
+// The hash is the hex encoded SHA-256 of the canonical JSON encoding of v, where the keys of all objects are sorted
+// and no insignificant whitespace is used, so that values with the same content have the same fingerprint
+// regardless of e.g. the order in which their maps were populated.
+func Fingerprint(v any) (string, error) {
+	if v != nil {
+		v = withoutTimestamps(reflect.ValueOf(v)).Interface()
+	}
+	data, err := jsonrs.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshalling value: %w", err)
+	}
+	decoder := jsonrs.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return "", fmt.Errorf("decoding value: %w", err)
+	}
+	var canonical bytes.Buffer
+	if err := writeCanonicalJSON(&canonical, decoded); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Fingerprint returns the [Fingerprint] of the workspace config.
+func (wc *WorkspaceConfig) Fingerprint() (string, error) {
+	return Fingerprint(wc)
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// withoutTimestamps returns a copy of v where the UpdatedAt and CreatedAt fields of all the reachable structs are
+// zeroed. Only the values that can hold structs are copied, the others are shared with v, and values held by
+// interfaces, e.g. the configs of entities, are left as they are.
+func withoutTimestamps(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !holdsStructs(v.Type()) {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(withoutTimestamps(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			switch {
+			case !field.IsExported():
+			case field.Type == timeType:
+				if field.Name == "UpdatedAt" || field.Name == "CreatedAt" {
+					copied.Field(i).SetZero()
+				}
+			default:
+				copied.Field(i).Set(withoutTimestamps(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice, reflect.Array:
+		if (v.Kind() == reflect.Slice && v.IsNil()) || !holdsStructs(v.Type()) {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		if v.Kind() == reflect.Slice {
+			copied.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		}
+		for i := range v.Len() {
+			copied.Index(i).Set(withoutTimestamps(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() || !holdsStructs(v.Type()) {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), withoutTimestamps(iter.Value()))
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// holdsStructs returns true if the values of a pointer, slice, array or map type can hold structs, without going
+// through interfaces.
+func holdsStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// writeCanonicalJSON writes the canonical JSON encoding of a value decoded from JSON (with numbers decoded as
+// json.Number).
+func writeCanonicalJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		buf.WriteString(v.String())
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		writeCanonicalString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		buf.WriteByte('{')
+		for i, key := range slices.Sorted(maps.Keys(v)) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", v)
+	}
+	return nil
+}
+
+// writeCanonicalString writes a JSON string, escaping only the characters that have to be escaped.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r < 0x20:
+			fmt.Fprintf(buf, `\u%04x`, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,73 @@
+package modelv2_test
+
+import (
+	"time"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// The golden values below only have the timestamp and live events fields of the entities of
+// testdata/sample_namespace.json.
+
+var goldenSources = map[string]*modelv2.Source{
+	"2hCDcJJGtZCIMDWjAyjsOg0W1Hr": {
+		CreatedBy:        "2fdoRuXbHt7VRLU7Zt4cHjdypkR",
+		CreatedAt:        time.Date(2024, 5, 30, 17, 14, 3, 570000000, time.UTC),
+		UpdatedAt:        time.Date(2024, 10, 16, 13, 35, 54, 256000000, time.UTC),
+		LiveEventsConfig: &modelv2.SourceLiveEventsConfig{EventUpload: false, EventUploadTS: 1729085754256},
+		GeoEnrichment:    &modelv2.GeoEnrichment{Enabled: false},
+	},
+	"2jIY89R3Ul5PxVsynuYapfmgtfP": {
+		CreatedBy:        "2be2oFX4OkMy3oKxJGLZQMTuqGA",
+		CreatedAt:        time.Date(2024, 7, 15, 20, 38, 15, 71000000, time.UTC),
+		UpdatedAt:        time.Date(2024, 8, 9, 14, 35, 7, 805000000, time.UTC),
+		LiveEventsConfig: &modelv2.SourceLiveEventsConfig{EventUpload: false, EventUploadTS: 1723214107805},
+		GeoEnrichment:    &modelv2.GeoEnrichment{Enabled: false},
+	},
+}
+
+var goldenDestinations = map[string]*modelv2.Destination{
+	"2i9lmpOsrP9KHqDR5hAlKWfNzb5": {
+		CreatedAt:        time.Date(2024, 6, 20, 19, 13, 55, 60000000, time.UTC),
+		UpdatedAt:        time.Date(2024, 10, 1, 9, 28, 13, 436000000, time.UTC),
+		LiveEventsConfig: &modelv2.DestinationLiveEventsConfig{},
+	},
+	"2hQdZuO7jPzgcw1uN9NaU1u2wPg": {
+		CreatedAt:        time.Date(2024, 6, 4, 19, 44, 53, 606000000, time.UTC),
+		UpdatedAt:        time.Date(2024, 10, 1, 9, 41, 58, 642000000, time.UTC),
+		LiveEventsConfig: &modelv2.DestinationLiveEventsConfig{},
+	},
+	"2nqWMUk1fNNAw4rU3bHYcm58CZZ": {
+		CreatedAt:        time.Date(2024, 10, 23, 16, 30, 38, 111000000, time.UTC),
+		UpdatedAt:        time.Date(2024, 11, 27, 20, 13, 30, 126000000, time.UTC),
+		LiveEventsConfig: &modelv2.DestinationLiveEventsConfig{},
+	},
+}
+
+var goldenTransformations = map[string]*modelv2.Transformation{
+	"2iQSOW2V2u9XWNRyxbrcgPeNyVP": {LiveEventsConfig: &modelv2.TransformationLiveEventsConfig{}},
+}
+
+var goldenSourceDefinitions = modelv2.SourceDefinitions{
+	"close_crm": {
+		CreatedAt: time.Date(2024, 7, 3, 9, 57, 46, 15000000, time.UTC),
+		UpdatedAt: time.Date(2024, 7, 3, 9, 57, 46, 15000000, time.UTC),
+	},
+	"singer-klaviyo": {
+		CreatedAt: time.Date(2022, 2, 1, 10, 51, 18, 705000000, time.UTC),
+		UpdatedAt: time.Date(2024, 7, 3, 9, 57, 50, 353000000, time.UTC),
+	},
+}
+
+var goldenDestinationDefinitions = modelv2.DestinationDefinitions{
+	"LINKEDIN_ADS": {
+		ResponseRules: map[string]any{},
+		CreatedAt:     time.Date(2024, 4, 4, 9, 33, 25, 123000000, time.UTC),
+		UpdatedAt:     time.Date(2024, 9, 19, 10, 35, 41, 520000000, time.UTC),
+	},
+	"SFTP": {
+		ResponseRules: map[string]any{},
+		CreatedAt:     time.Date(2024, 5, 16, 10, 45, 48, 349000000, time.UTC),
+		UpdatedAt:     time.Date(2024, 9, 19, 10, 35, 9, 49000000, time.UTC),
+	},
+}
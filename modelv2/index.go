@@ -0,0 +1,209 @@
+package modelv2
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+)
+
+// SourceRef is a source together with the IDs identifying it.
+type SourceRef struct {
+	WorkspaceID string
+	SourceID    string
+	Source      *Source
+}
+
+// DestinationRef is a destination connected to a source, together with the IDs identifying it and the connection.
+type DestinationRef struct {
+	WorkspaceID   string
+	DestinationID string
+	Destination   *Destination
+	Connection    *Connection
+}
+
+// AccountRef is an account together with the IDs identifying it.
+type AccountRef struct {
+	WorkspaceID string
+	AccountID   string
+	Account     *Account
+}
+
+// Index provides lookups over WorkspaceConfigs that would otherwise require scanning all of its workspaces.
+// Entities are indexed regardless of whether they are enabled or deleted, it is up to the caller to check that.
+// The index references the entities of the indexed WorkspaceConfigs, thus it has to be updated whenever they change.
+// Its methods are safe for concurrent use.
+type Index struct {
+	mu                   sync.RWMutex
+	workspaces           map[string]*indexedWorkspace
+	sourcesByWriteKey    map[string]SourceRef
+	sources              map[string]SourceRef
+	destinationsBySource map[string][]DestinationRef
+	accounts             map[string]AccountRef
+}
+
+// indexedWorkspace keeps track of the keys a workspace was indexed with, so that they can be removed later on.
+type indexedWorkspace struct {
+	writeKeys  []string
+	sourceIDs  []string
+	accountIDs []string
+}
+
+// NewIndex returns an empty index, use [Index.Build] or [Index.Update] to populate it.
+func NewIndex() *Index {
+	return &Index{
+		workspaces:           make(map[string]*indexedWorkspace),
+		sourcesByWriteKey:    make(map[string]SourceRef),
+		sources:              make(map[string]SourceRef),
+		destinationsBySource: make(map[string][]DestinationRef),
+		accounts:             make(map[string]AccountRef),
+	}
+}
+
+// Build indexes all the workspaces of wcs from scratch.
+func (ix *Index) Build(wcs *WorkspaceConfigs) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	clear(ix.workspaces)
+	clear(ix.sourcesByWriteKey)
+	clear(ix.sources)
+	clear(ix.destinationsBySource)
+	clear(ix.accounts)
+	for workspaceID, wc := range wcs.Workspaces {
+		ix.add(workspaceID, wc)
+	}
+}
+
+// Update indexes again only the workspaces of wcs that changed, as reported by [diff.Updater.Changes] for the
+// Workspaces list, e.g.:
+//
+//	index.Update(cache, updater.Changes()[cache.Workspaces.Type()])
+func (ix *Index) Update(wcs *WorkspaceConfigs, changes diff.Changes[string]) {
+	if changes.IsEmpty() {
+		return
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for _, workspaceID := range changes.Removed {
+		ix.remove(workspaceID)
+	}
+	for _, workspaceID := range slices.Concat(changes.Added, changes.Changed) {
+		ix.remove(workspaceID)
+		if wc, ok := wcs.Workspaces[workspaceID]; ok {
+			ix.add(workspaceID, wc)
+		}
+	}
+}
+
+// SourceByWriteKey returns the source with the given write key.
+func (ix *Index) SourceByWriteKey(writeKey string) (SourceRef, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	ref, ok := ix.sourcesByWriteKey[writeKey]
+	return ref, ok
+}
+
+// Source returns the source with the given ID.
+func (ix *Index) Source(sourceID string) (SourceRef, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	ref, ok := ix.sources[sourceID]
+	return ref, ok
+}
+
+// SourceWorkspace returns the ID of the workspace owning the source with the given ID.
+func (ix *Index) SourceWorkspace(sourceID string) (string, bool) {
+	ref, ok := ix.Source(sourceID)
+	return ref.WorkspaceID, ok
+}
+
+// DestinationsBySource returns the destinations connected to the source with the given ID, sorted by destination ID.
+// The returned slice is shared and must not be modified.
+func (ix *Index) DestinationsBySource(sourceID string) []DestinationRef {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.destinationsBySource[sourceID]
+}
+
+// Account returns the account with the given ID, regardless of the workspace it belongs to.
+func (ix *Index) Account(accountID string) (AccountRef, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	ref, ok := ix.accounts[accountID]
+	return ref, ok
+}
+
+func (ix *Index) add(workspaceID string, wc *WorkspaceConfig) {
+	if wc == nil {
+		return
+	}
+	iw := &indexedWorkspace{}
+	ix.workspaces[workspaceID] = iw
+
+	for sourceID, source := range wc.Sources {
+		ref := SourceRef{WorkspaceID: workspaceID, SourceID: sourceID, Source: source}
+		ix.sources[sourceID] = ref
+		iw.sourceIDs = append(iw.sourceIDs, sourceID)
+		if source != nil && source.WriteKey != "" {
+			ix.sourcesByWriteKey[source.WriteKey] = ref
+			iw.writeKeys = append(iw.writeKeys, source.WriteKey)
+		}
+	}
+	for _, conn := range wc.Connections {
+		if conn == nil {
+			continue
+		}
+		if _, ok := wc.Sources[conn.SourceID]; !ok {
+			continue
+		}
+		destination, ok := wc.Destinations[conn.DestinationID]
+		if !ok {
+			continue
+		}
+		ix.destinationsBySource[conn.SourceID] = append(ix.destinationsBySource[conn.SourceID], DestinationRef{
+			WorkspaceID:   workspaceID,
+			DestinationID: conn.DestinationID,
+			Destination:   destination,
+			Connection:    conn,
+		})
+	}
+	for _, sourceID := range iw.sourceIDs {
+		slices.SortFunc(ix.destinationsBySource[sourceID], func(a, b DestinationRef) int {
+			return strings.Compare(a.DestinationID, b.DestinationID)
+		})
+	}
+	for accountID, account := range wc.Accounts {
+		ix.accounts[accountID] = AccountRef{WorkspaceID: workspaceID, AccountID: accountID, Account: account}
+		iw.accountIDs = append(iw.accountIDs, accountID)
+	}
+}
+
+func (ix *Index) remove(workspaceID string) {
+	iw, ok := ix.workspaces[workspaceID]
+	if !ok {
+		return
+	}
+	delete(ix.workspaces, workspaceID)
+
+	// only remove the keys that still belong to the workspace, in case another one claimed them in the meantime
+	for _, writeKey := range iw.writeKeys {
+		if ix.sourcesByWriteKey[writeKey].WorkspaceID == workspaceID {
+			delete(ix.sourcesByWriteKey, writeKey)
+		}
+	}
+	for _, sourceID := range iw.sourceIDs {
+		if ix.sources[sourceID].WorkspaceID == workspaceID {
+			delete(ix.sources, sourceID)
+			delete(ix.destinationsBySource, sourceID)
+		}
+	}
+	for _, accountID := range iw.accountIDs {
+		if ix.accounts[accountID].WorkspaceID == workspaceID {
+			delete(ix.accounts, accountID)
+		}
+	}
+}
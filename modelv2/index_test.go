@@ -0,0 +1,116 @@
+package modelv2_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestIndex(t *testing.T) {
+	data, err := os.ReadFile("../testdata/sample_namespace.json")
+	require.NoError(t, err)
+	response := &modelv2.WorkspaceConfigs{}
+	require.NoError(t, jsonrs.Unmarshal(data, response))
+
+	cache := &modelv2.WorkspaceConfigs{}
+	updater := &diff.Updater[string]{}
+	_, _, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+
+	index := modelv2.NewIndex()
+	index.Update(cache, updater.Changes()[cache.Workspaces.Type()])
+
+	ref, ok := index.SourceByWriteKey("some-write-key-for-source-1")
+	require.True(t, ok)
+	require.Equal(t, "2hCBi02C8xYS8Rsy1m9bJjTlKy6", ref.WorkspaceID)
+	require.Equal(t, "2hCDcJJGtZCIMDWjAyjsOg0W1Hr", ref.SourceID)
+	require.Same(t, cache.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Sources["2hCDcJJGtZCIMDWjAyjsOg0W1Hr"], ref.Source)
+	_, ok = index.SourceByWriteKey("unknown")
+	require.False(t, ok)
+
+	workspaceID, ok := index.SourceWorkspace("2jIY89R3Ul5PxVsynuYapfmgtfP")
+	require.True(t, ok)
+	require.Equal(t, "2bVMV2JiAJe42OXZrzyvJI75v0N", workspaceID)
+
+	destinations := index.DestinationsBySource("2hCDcJJGtZCIMDWjAyjsOg0W1Hr")
+	require.Len(t, destinations, 2)
+	require.Equal(t, "2hQdZuO7jPzgcw1uN9NaU1u2wPg", destinations[0].DestinationID)
+	require.Equal(t, "BQ", destinations[0].Destination.DefinitionName)
+	require.Equal(t, "2i9lmpOsrP9KHqDR5hAlKWfNzb5", destinations[1].DestinationID)
+	require.Equal(t, "MP", destinations[1].Destination.DefinitionName)
+	require.True(t, destinations[1].Connection.ProcessorEnabled)
+	require.Empty(t, index.DestinationsBySource("unknown"))
+
+	account, ok := index.Account("2gViSzlt6hyqKmPsrdkFaC1gxke")
+	require.True(t, ok)
+	require.Equal(t, "2bVMV2JiAJe42OXZrzyvJI75v0N", account.WorkspaceID)
+	require.Equal(t, "Account name 1", account.Account.Name)
+
+	// the second workspace is removed and the first one is updated, moving its source to a new write key
+	updatedAt := cache.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"].UpdatedAt.Add(time.Minute)
+	response = &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{
+		"2hCBi02C8xYS8Rsy1m9bJjTlKy6": {
+			Sources: map[string]*modelv2.Source{
+				"2hCDcJJGtZCIMDWjAyjsOg0W1Hr": {Name: "Source 1", WriteKey: "new-write-key"},
+			},
+			UpdatedAt: updatedAt,
+		},
+	}}
+	_, _, err = updater.UpdateCache(response, cache)
+	require.NoError(t, err)
+	index.Update(cache, updater.Changes()[cache.Workspaces.Type()])
+
+	_, ok = index.SourceByWriteKey("some-write-key-for-source-1")
+	require.False(t, ok)
+	ref, ok = index.SourceByWriteKey("new-write-key")
+	require.True(t, ok)
+	require.Equal(t, "2hCDcJJGtZCIMDWjAyjsOg0W1Hr", ref.SourceID)
+	require.Empty(t, index.DestinationsBySource("2hCDcJJGtZCIMDWjAyjsOg0W1Hr"))
+	_, ok = index.SourceWorkspace("2jIY89R3Ul5PxVsynuYapfmgtfP")
+	require.False(t, ok)
+	_, ok = index.Account("2gViSzlt6hyqKmPsrdkFaC1gxke")
+	require.False(t, ok)
+
+	// a full build should give the same result
+	rebuilt := modelv2.NewIndex()
+	rebuilt.Build(cache)
+	rebuiltRef, ok := rebuilt.SourceByWriteKey("new-write-key")
+	require.True(t, ok)
+	require.Equal(t, ref, rebuiltRef)
+	_, ok = rebuilt.SourceByWriteKey("some-write-key-for-source-1")
+	require.False(t, ok)
+	_, ok = rebuilt.Account("2gViSzlt6hyqKmPsrdkFaC1gxke")
+	require.False(t, ok)
+}
+
+func TestIndexConcurrency(t *testing.T) {
+	wcs := &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{
+		"ws-1": {Sources: map[string]*modelv2.Source{"src-1": {WriteKey: "wk-1"}}},
+	}}
+	index := modelv2.NewIndex()
+	index.Build(wcs)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			for range 100 {
+				_, ok := index.SourceByWriteKey("wk-1")
+				require.True(t, ok)
+			}
+		})
+	}
+	wg.Go(func() {
+		for range 100 {
+			index.Update(wcs, diff.Changes[string]{Changed: []string{"ws-1"}})
+		}
+	})
+	wg.Wait()
+}
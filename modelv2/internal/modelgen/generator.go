@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type spec struct {
+	dir    string
+	output string
+	types  []string
+}
+
+// generator renders the methods of the reachable named types, keeping track of the types still to render and of
+// the packages to import.
+type generator struct {
+	decls    map[string]ast.Expr
+	declared map[string]map[string]struct{} // methods declared by hand, by receiver type
+	pending  []string
+	seen     map[string]struct{}
+	imports  map[string]struct{}
+}
+
+func generate(s spec) ([]byte, error) {
+	if len(s.types) == 0 {
+		return nil, fmt.Errorf("at least one type is required")
+	}
+	pkg, decls, declared, err := parseDir(s.dir, s.output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{decls: decls, declared: declared, seen: make(map[string]struct{}), imports: make(map[string]struct{})}
+	for _, name := range s.types {
+		if _, ok := decls[name]; !ok {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+		g.enqueue(name)
+	}
+
+	bodies := make(map[string]string)
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		body, err := g.methods(name)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		bodies[name] = body
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by modelgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, imp := range sortedKeys(g.imports) {
+			fmt.Fprintf(&buf, "\t%q\n", imp)
+		}
+		buf.WriteString(")\n\n")
+	}
+	for _, name := range sortedKeys(bodies) {
+		buf.WriteString(bodies[name])
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parseDir returns the package name, the named (non generic, non alias) types and the methods declared by the non
+// test Go files of dir, ignoring the previously generated output.
+func parseDir(dir, output string) (string, map[string]ast.Expr, map[string]map[string]struct{}, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var (
+		pkg      string
+		decls    = make(map[string]ast.Expr)
+		declared = make(map[string]map[string]struct{})
+		fset     = token.NewFileSet()
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil {
+				recv := embeddedName(fd.Recv.List[0].Type)
+				if declared[recv] == nil {
+					declared[recv] = make(map[string]struct{})
+				}
+				declared[recv][fd.Name.Name] = struct{}{}
+				continue
+			}
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gd.Specs {
+				ts := s.(*ast.TypeSpec)
+				if ts.Assign.IsValid() || ts.TypeParams != nil {
+					continue
+				}
+				decls[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+	if pkg == "" {
+		return "", nil, nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return pkg, decls, declared, nil
+}
+
+// isDeclared tells whether the method of a type is declared by hand, in which case it is not generated.
+func (g *generator) isDeclared(name, method string) bool {
+	_, ok := g.declared[name][method]
+	return ok
+}
+
+func (g *generator) enqueue(name string) {
+	if _, ok := g.seen[name]; ok {
+		return
+	}
+	g.seen[name] = struct{}{}
+	g.pending = append(g.pending, name)
+}
+
+// methods renders the DeepCopyInto, DeepCopy and, if it has an Extra field, the JSON methods of a struct type, or
+// the DeepCopy method of a map or slice type.
+func (g *generator) methods(name string) (string, error) {
+	var buf bytes.Buffer
+	switch t := g.decls[name].(type) {
+	case *ast.StructType:
+		fmt.Fprintf(&buf, "// DeepCopyInto copies the receiver into out, which must be non-nil.\n")
+		fmt.Fprintf(&buf, "func (in *%s) DeepCopyInto(out *%s) {\n", name, name)
+		buf.WriteString("*out = *in\n")
+		for _, field := range t.Fields.List {
+			names := field.Names
+			if len(names) == 0 { // embedded field
+				names = []*ast.Ident{ast.NewIdent(embeddedName(field.Type))}
+			}
+			for _, n := range names {
+				src, dst := "in."+n.Name, "out."+n.Name
+				if g.isStruct(field.Type) {
+					g.enqueue(field.Type.(*ast.Ident).Name)
+					fmt.Fprintf(&buf, "%s.DeepCopyInto(&%s)\n", src, dst)
+					continue
+				}
+				expr, ok, err := g.copyExpr(src, field.Type)
+				if err != nil {
+					return "", fmt.Errorf("field %s: %w", n.Name, err)
+				}
+				if ok {
+					fmt.Fprintf(&buf, "%s = %s\n", dst, expr)
+				}
+			}
+		}
+		buf.WriteString("}\n\n")
+		fmt.Fprintf(&buf, "// DeepCopy returns a deep copy of the %s.\n", name)
+		fmt.Fprintf(&buf, "func (in *%s) DeepCopy() *%s {\n", name, name)
+		fmt.Fprintf(&buf, "if in == nil {\nreturn nil\n}\nout := new(%s)\nin.DeepCopyInto(out)\nreturn out\n}\n\n", name)
+		if hasExtra(t) && !g.isDeclared(name, "UnmarshalJSON") {
+			fmt.Fprintf(&buf, "// UnmarshalJSON decodes the %s, keeping the fields it doesn't model in Extra.\n", name)
+			fmt.Fprintf(&buf, "func (in *%s) UnmarshalJSON(data []byte) error {\n", name)
+			fmt.Fprintf(&buf, "type plain %s\nreturn unmarshalWithExtra(data, (*plain)(in), &in.Extra)\n}\n\n", name)
+		}
+		if hasExtra(t) && !g.isDeclared(name, "MarshalJSON") {
+			fmt.Fprintf(&buf, "// MarshalJSON encodes the %s, including the fields in Extra.\n", name)
+			fmt.Fprintf(&buf, "func (in %s) MarshalJSON() ([]byte, error) {\n", name)
+			fmt.Fprintf(&buf, "type plain %s\nreturn marshalWithExtra(plain(in), in.Extra)\n}\n\n", name)
+		}
+	case *ast.MapType, *ast.ArrayType:
+		expr, ok, err := g.copyExpr("in", t)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			expr = "in"
+		}
+		fmt.Fprintf(&buf, "// DeepCopy returns a deep copy of the %s.\n", name)
+		fmt.Fprintf(&buf, "func (in %s) DeepCopy() %s {\nreturn %s\n}\n\n", name, name, expr)
+	default:
+		return "", fmt.Errorf("unsupported type %s", types.ExprString(t))
+	}
+	return buf.String(), nil
+}
+
+// copyExpr returns an expression evaluating to a deep copy of src, which is of type t. If values of type t don't need
+// to be copied (e.g. strings), ok is false.
+func (g *generator) copyExpr(src string, t ast.Expr) (expr string, ok bool, err error) {
+	switch t := t.(type) {
+	case *ast.Ident:
+		if t.Name == "any" {
+			return "deepCopyValue(" + src + ")", true, nil
+		}
+		decl, declared := g.decls[t.Name]
+		if !declared {
+			return "", false, nil // predeclared type, e.g. string
+		}
+		switch decl.(type) {
+		case *ast.StructType:
+			g.enqueue(t.Name)
+			return "*" + src + ".DeepCopy()", true, nil
+		case *ast.MapType, *ast.ArrayType:
+			g.enqueue(t.Name)
+			return src + ".DeepCopy()", true, nil
+		default:
+			if _, needsCopy, err := g.copyExpr(src, decl); err != nil || needsCopy {
+				return "", false, fmt.Errorf("unsupported type %s", t.Name)
+			}
+			return "", false, nil
+		}
+	case *ast.InterfaceType:
+		if len(t.Methods.List) > 0 {
+			return "", false, fmt.Errorf("unsupported type %s", types.ExprString(t))
+		}
+		return "deepCopyValue(" + src + ")", true, nil
+	case *ast.SelectorExpr:
+		if types.ExprString(t) == "json.RawMessage" {
+			g.imports["slices"] = struct{}{}
+			return "slices.Clone(" + src + ")", true, nil
+		}
+		return "", false, nil // other types of other packages are copied by value, e.g. time.Time
+	case *ast.StarExpr:
+		if g.isStruct(t.X) {
+			g.enqueue(t.X.(*ast.Ident).Name)
+			return src + ".DeepCopy()", true, nil
+		}
+		if _, needsCopy, err := g.copyExpr(src, t.X); err != nil || needsCopy {
+			return "", false, fmt.Errorf("unsupported type %s", types.ExprString(t))
+		}
+		return "clonePointer(" + src + ")", true, nil
+	case *ast.MapType:
+		fn, needsCopy, err := g.copyFunc(t.Value)
+		if err != nil {
+			return "", false, err
+		}
+		if !needsCopy {
+			g.imports["maps"] = struct{}{}
+			return "maps.Clone(" + src + ")", true, nil
+		}
+		return "deepCopyMap(" + src + ", " + fn + ")", true, nil
+	case *ast.ArrayType:
+		fn, needsCopy, err := g.copyFunc(t.Elt)
+		if err != nil {
+			return "", false, err
+		}
+		if t.Len != nil {
+			if needsCopy {
+				return "", false, fmt.Errorf("unsupported type %s", types.ExprString(t))
+			}
+			return "", false, nil
+		}
+		if !needsCopy {
+			g.imports["slices"] = struct{}{}
+			return "slices.Clone(" + src + ")", true, nil
+		}
+		return "deepCopySlice(" + src + ", " + fn + ")", true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported type %s", types.ExprString(t))
+	}
+}
+
+// copyFunc returns a function deep copying values of type t, preferring method expressions over function literals.
+func (g *generator) copyFunc(t ast.Expr) (fn string, ok bool, err error) {
+	expr, ok, err := g.copyExpr("v", t)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	typ := types.ExprString(t)
+	switch {
+	case expr == "deepCopyValue(v)":
+		return "deepCopyValue", true, nil
+	case expr == "v.DeepCopy()" && strings.HasPrefix(typ, "*"):
+		return "(" + typ + ").DeepCopy", true, nil
+	case expr == "v.DeepCopy()":
+		return typ + ".DeepCopy", true, nil
+	}
+	if err := g.importPackages(t); err != nil {
+		return "", false, err
+	}
+	return "func(v " + typ + ") " + typ + " { return " + expr + " }", true, nil
+}
+
+// knownPackages are the import paths of the packages whose types can be referenced by the generated code.
+var knownPackages = map[string]string{
+	"json": "encoding/json",
+	"time": "time",
+}
+
+// importPackages imports the packages of the types referenced by t.
+func (g *generator) importPackages(t ast.Expr) (err error) {
+	ast.Inspect(t, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		pkg := types.ExprString(sel.X)
+		path, known := knownPackages[pkg]
+		if !known {
+			err = fmt.Errorf("unknown package %s", pkg)
+			return false
+		}
+		g.imports[path] = struct{}{}
+		return false
+	})
+	return err
+}
+
+// isStruct returns true if t is the name of a struct type declared by the package.
+func (g *generator) isStruct(t ast.Expr) bool {
+	id, ok := t.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = g.decls[id.Name].(*ast.StructType)
+	return ok
+}
+
+// hasExtra returns true if the struct has an Extra field.
+func hasExtra(t *ast.StructType) bool {
+	for _, field := range t.Fields.List {
+		for _, n := range field.Names {
+			if n.Name == "Extra" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func embeddedName(t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	default:
+		return types.ExprString(t)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("modelv2 is up to date", func(t *testing.T) {
+		args := goGenerateArgs(t, "../../workspaceconfigs.go")
+		expected, err := os.ReadFile("../../model_gen.go")
+		require.NoError(t, err)
+
+		actual, err := generate(spec{dir: "../..", output: "model_gen.go", types: args})
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(actual), "run go generate ./... to update modelv2")
+	})
+
+	t.Run("supported types", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), []byte(`package test
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Kind string
+
+type Root struct {
+	Kind      Kind
+	At        time.Time
+	Value     any
+	Pointer   *string
+	Strings   []string
+	Counts    map[string]int
+	Children  Children
+	Nested    [][]*Child
+	Child     Child
+	Optional  *Child
+	ByKind    map[Kind][]any
+}
+
+type Children map[string]*Child
+
+type Child struct {
+	Name  string
+	Extra Extra
+}
+
+type Extra map[string]json.RawMessage
+
+type Custom struct {
+	Name  string
+	Extra Extra
+}
+
+func (in *Custom) UnmarshalJSON(data []byte) error { return nil }
+`), 0o644))
+
+		src, err := generate(spec{dir: dir, output: "model_gen.go", types: []string{"Root", "Custom"}})
+		require.NoError(t, err)
+		for _, expected := range []string{
+			"package test",
+			"func (in *Root) DeepCopyInto(out *Root) {",
+			"out.Value = deepCopyValue(in.Value)",
+			"out.Pointer = clonePointer(in.Pointer)",
+			"out.Strings = slices.Clone(in.Strings)",
+			"out.Counts = maps.Clone(in.Counts)",
+			"out.Children = in.Children.DeepCopy()",
+			"out.Nested = deepCopySlice(in.Nested, func(v []*Child) []*Child { return deepCopySlice(v, (*Child).DeepCopy) })",
+			"in.Child.DeepCopyInto(&out.Child)",
+			"out.Optional = in.Optional.DeepCopy()",
+			"out.ByKind = deepCopyMap(in.ByKind, func(v []any) []any { return deepCopySlice(v, deepCopyValue) })",
+			"func (in Children) DeepCopy() Children {",
+			"return deepCopyMap(in, (*Child).DeepCopy)",
+			"func (in *Child) DeepCopy() *Child {",
+			"out.Extra = in.Extra.DeepCopy()",
+			"\"encoding/json\"",
+			"return deepCopyMap(in, func(v json.RawMessage) json.RawMessage { return slices.Clone(v) })",
+			"func (in *Child) UnmarshalJSON(data []byte) error {",
+			"return unmarshalWithExtra(data, (*plain)(in), &in.Extra)",
+			"func (in Child) MarshalJSON() ([]byte, error) {",
+			"return marshalWithExtra(plain(in), in.Extra)",
+			"func (in Custom) MarshalJSON() ([]byte, error) {",
+		} {
+			require.Contains(t, string(src), expected)
+		}
+		require.NotContains(t, string(src), "out.Kind")
+		require.NotContains(t, string(src), "out.At")
+		require.NotContains(t, string(src), "func (in *Root) UnmarshalJSON")
+		require.NotContains(t, string(src), "func (in *Custom) UnmarshalJSON", "methods declared by hand are not generated")
+	})
+
+	for _, tc := range []struct {
+		name     string
+		types    []string
+		src      string
+		expected string
+	}{
+		{
+			name:     "no types",
+			src:      "package test\n",
+			expected: "at least one type is required",
+		},
+		{
+			name:     "unknown type",
+			types:    []string{"Foo"},
+			src:      "package test\n",
+			expected: `unknown type "Foo"`,
+		},
+		{
+			name:     "unsupported field",
+			types:    []string{"Foo"},
+			src:      "package test\n\ntype Foo struct {\n\tFn func()\n}\n",
+			expected: "type Foo: field Fn: unsupported type func()",
+		},
+		{
+			name:     "unsupported pointer",
+			types:    []string{"Foo"},
+			src:      "package test\n\ntype Foo struct {\n\tM *map[string]any\n}\n",
+			expected: "type Foo: field M: unsupported type *map[string]any",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), []byte(tc.src), 0o644))
+			_, err := generate(spec{dir: dir, output: "model_gen.go", types: tc.types})
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+// goGenerateArgs returns the types passed to modelgen by the go:generate directive in the given file.
+func goGenerateArgs(t *testing.T, file string) []string {
+	t.Helper()
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		directive, ok := strings.CutPrefix(s.Text(), "//go:generate go run ./internal/modelgen")
+		if !ok {
+			continue
+		}
+		var types []string
+		fields := strings.Fields(directive)
+		for i := 0; i+1 < len(fields); i += 2 {
+			require.Equal(t, "-type", fields[i])
+			types = append(types, fields[i+1])
+		}
+		return types
+	}
+	require.NoError(t, s.Err())
+	require.FailNow(t, "go:generate directive not found")
+	return nil
+}
@@ -0,0 +1,53 @@
+// modelgen generates the boilerplate methods of the modelv2 types, starting from the given root types and following
+// their fields, e.g.:
+//
+//	//go:generate go run ./internal/modelgen -type WorkspaceConfigs -type AccountSecret
+//
+// The DeepCopy and DeepCopyInto methods are generated for all struct types, and the DeepCopy method for all map and
+// slice types. Fields of types declared in other packages (e.g. time.Time) are copied by value, except for
+// json.RawMessage, while fields of type any are copied with deepCopyValue, which has to be declared by the package.
+//
+// Struct types with an Extra field also get UnmarshalJSON and MarshalJSON methods, keeping the JSON fields they don't
+// model in Extra, unless the package declares them by hand. They use unmarshalWithExtra and marshalWithExtra, which
+// have to be declared by the package.
+//
+// Types are read from the Go files of the current directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "modelgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	s := spec{dir: ".", output: "model_gen.go"}
+
+	fs := flag.NewFlagSet("modelgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&s.dir, "dir", s.dir, "directory of the package declaring the types")
+	fs.StringVar(&s.output, "output", s.output, "name of the generated file, relative to dir")
+	fs.Func("type", "root type to generate the methods for, can be repeated", func(v string) error {
+		s.types = append(s.types, strings.TrimSpace(v))
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := generate(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, s.output), src, 0o644)
+}
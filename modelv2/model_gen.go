@@ -0,0 +1,907 @@
+// Code generated by modelgen. DO NOT EDIT.
+
+package modelv2
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Account) DeepCopyInto(out *Account) {
+	*out = *in
+	out.Options = deepCopyMap(in.Options, deepCopyValue)
+	out.Secret = deepCopyMap(in.Secret, deepCopyValue)
+	out.Metadata = deepCopyMap(in.Metadata, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Account.
+func (in *Account) DeepCopy() *Account {
+	if in == nil {
+		return nil
+	}
+	out := new(Account)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Account, keeping the fields it doesn't model in Extra.
+func (in *Account) UnmarshalJSON(data []byte) error {
+	type plain Account
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Account, including the fields in Extra.
+func (in Account) MarshalJSON() ([]byte, error) {
+	type plain Account
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *AccountSecret) DeepCopyInto(out *AccountSecret) {
+	*out = *in
+	out.Secret = deepCopyMap(in.Secret, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the AccountSecret.
+func (in *AccountSecret) DeepCopy() *AccountSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the AccountSecret, keeping the fields it doesn't model in Extra.
+func (in *AccountSecret) UnmarshalJSON(data []byte) error {
+	type plain AccountSecret
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the AccountSecret, including the fields in Extra.
+func (in AccountSecret) MarshalJSON() ([]byte, error) {
+	type plain AccountSecret
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *AudienceSource) DeepCopyInto(out *AudienceSource) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the AudienceSource.
+func (in *AudienceSource) DeepCopy() *AudienceSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AudienceSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the AudienceSource, keeping the fields it doesn't model in Extra.
+func (in *AudienceSource) UnmarshalJSON(data []byte) error {
+	type plain AudienceSource
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the AudienceSource, including the fields in Extra.
+func (in AudienceSource) MarshalJSON() ([]byte, error) {
+	type plain AudienceSource
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Bucket) DeepCopyInto(out *Bucket) {
+	*out = *in
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Bucket.
+func (in *Bucket) DeepCopy() *Bucket {
+	if in == nil {
+		return nil
+	}
+	out := new(Bucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Bucket, keeping the fields it doesn't model in Extra.
+func (in *Bucket) UnmarshalJSON(data []byte) error {
+	type plain Bucket
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Bucket, including the fields in Extra.
+func (in Bucket) MarshalJSON() ([]byte, error) {
+	type plain Bucket
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Connection) DeepCopyInto(out *Connection) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Connection.
+func (in *Connection) DeepCopy() *Connection {
+	if in == nil {
+		return nil
+	}
+	out := new(Connection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Connection, keeping the fields it doesn't model in Extra.
+func (in *Connection) UnmarshalJSON(data []byte) error {
+	type plain Connection
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Connection, including the fields in Extra.
+func (in Connection) MarshalJSON() ([]byte, error) {
+	type plain Connection
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DGSourceTrackingPlan) DeepCopyInto(out *DGSourceTrackingPlan) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DGSourceTrackingPlan.
+func (in *DGSourceTrackingPlan) DeepCopy() *DGSourceTrackingPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DGSourceTrackingPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DGSourceTrackingPlan, keeping the fields it doesn't model in Extra.
+func (in *DGSourceTrackingPlan) UnmarshalJSON(data []byte) error {
+	type plain DGSourceTrackingPlan
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DGSourceTrackingPlan, including the fields in Extra.
+func (in DGSourceTrackingPlan) MarshalJSON() ([]byte, error) {
+	type plain DGSourceTrackingPlan
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DGSourceTrackingPlanConfig) DeepCopyInto(out *DGSourceTrackingPlanConfig) {
+	*out = *in
+	out.Global = in.Global.DeepCopy()
+	out.Track = in.Track.DeepCopy()
+	out.Identify = in.Identify.DeepCopy()
+	out.Group = in.Group.DeepCopy()
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DGSourceTrackingPlanConfig.
+func (in *DGSourceTrackingPlanConfig) DeepCopy() *DGSourceTrackingPlanConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DGSourceTrackingPlanConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DGSourceTrackingPlanConfig, keeping the fields it doesn't model in Extra.
+func (in *DGSourceTrackingPlanConfig) UnmarshalJSON(data []byte) error {
+	type plain DGSourceTrackingPlanConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DGSourceTrackingPlanConfig, including the fields in Extra.
+func (in DGSourceTrackingPlanConfig) MarshalJSON() ([]byte, error) {
+	type plain DGSourceTrackingPlanConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DGSourceTrackingPlanOptions) DeepCopyInto(out *DGSourceTrackingPlanOptions) {
+	*out = *in
+	out.AJVOptions = deepCopyMap(in.AJVOptions, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DGSourceTrackingPlanOptions.
+func (in *DGSourceTrackingPlanOptions) DeepCopy() *DGSourceTrackingPlanOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DGSourceTrackingPlanOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DGSourceTrackingPlanOptions, keeping the fields it doesn't model in Extra.
+func (in *DGSourceTrackingPlanOptions) UnmarshalJSON(data []byte) error {
+	type plain DGSourceTrackingPlanOptions
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DGSourceTrackingPlanOptions, including the fields in Extra.
+func (in DGSourceTrackingPlanOptions) MarshalJSON() ([]byte, error) {
+	type plain DGSourceTrackingPlanOptions
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DataRetention) DeepCopyInto(out *DataRetention) {
+	*out = *in
+	in.StorageBucket.DeepCopyInto(&out.StorageBucket)
+	in.StoragePreferences.DeepCopyInto(&out.StoragePreferences)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DataRetention.
+func (in *DataRetention) DeepCopy() *DataRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(DataRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DataRetention, keeping the fields it doesn't model in Extra.
+func (in *DataRetention) UnmarshalJSON(data []byte) error {
+	type plain DataRetention
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DataRetention, including the fields in Extra.
+func (in DataRetention) MarshalJSON() ([]byte, error) {
+	type plain DataRetention
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DataRetentionStoragePreferences) DeepCopyInto(out *DataRetentionStoragePreferences) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DataRetentionStoragePreferences.
+func (in *DataRetentionStoragePreferences) DeepCopy() *DataRetentionStoragePreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(DataRetentionStoragePreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DataRetentionStoragePreferences, keeping the fields it doesn't model in Extra.
+func (in *DataRetentionStoragePreferences) UnmarshalJSON(data []byte) error {
+	type plain DataRetentionStoragePreferences
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DataRetentionStoragePreferences, including the fields in Extra.
+func (in DataRetentionStoragePreferences) MarshalJSON() ([]byte, error) {
+	type plain DataRetentionStoragePreferences
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Destination) DeepCopyInto(out *Destination) {
+	*out = *in
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.TransformationIDs = slices.Clone(in.TransformationIDs)
+	out.Regions = deepCopySlice(in.Regions, (*DestinationRegion).DeepCopy)
+	out.LiveEventsConfig = in.LiveEventsConfig.DeepCopy()
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Destination.
+func (in *Destination) DeepCopy() *Destination {
+	if in == nil {
+		return nil
+	}
+	out := new(Destination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Destination, keeping the fields it doesn't model in Extra.
+func (in *Destination) UnmarshalJSON(data []byte) error {
+	type plain Destination
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Destination, including the fields in Extra.
+func (in Destination) MarshalJSON() ([]byte, error) {
+	type plain Destination
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DestinationDefinition) DeepCopyInto(out *DestinationDefinition) {
+	*out = *in
+	out.Options = deepCopyMap(in.Options, deepCopyValue)
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.ResponseRules = deepCopyMap(in.ResponseRules, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DestinationDefinition.
+func (in *DestinationDefinition) DeepCopy() *DestinationDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DestinationDefinition, keeping the fields it doesn't model in Extra.
+func (in *DestinationDefinition) UnmarshalJSON(data []byte) error {
+	type plain DestinationDefinition
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DestinationDefinition, including the fields in Extra.
+func (in DestinationDefinition) MarshalJSON() ([]byte, error) {
+	type plain DestinationDefinition
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopy returns a deep copy of the DestinationDefinitions.
+func (in DestinationDefinitions) DeepCopy() DestinationDefinitions {
+	return deepCopyMap(in, (*DestinationDefinition).DeepCopy)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DestinationLiveEventsConfig) DeepCopyInto(out *DestinationLiveEventsConfig) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DestinationLiveEventsConfig.
+func (in *DestinationLiveEventsConfig) DeepCopy() *DestinationLiveEventsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationLiveEventsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DestinationLiveEventsConfig, keeping the fields it doesn't model in Extra.
+func (in *DestinationLiveEventsConfig) UnmarshalJSON(data []byte) error {
+	type plain DestinationLiveEventsConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DestinationLiveEventsConfig, including the fields in Extra.
+func (in DestinationLiveEventsConfig) MarshalJSON() ([]byte, error) {
+	type plain DestinationLiveEventsConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DestinationRegion) DeepCopyInto(out *DestinationRegion) {
+	*out = *in
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the DestinationRegion.
+func (in *DestinationRegion) DeepCopy() *DestinationRegion {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationRegion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the DestinationRegion, keeping the fields it doesn't model in Extra.
+func (in *DestinationRegion) UnmarshalJSON(data []byte) error {
+	type plain DestinationRegion
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the DestinationRegion, including the fields in Extra.
+func (in DestinationRegion) MarshalJSON() ([]byte, error) {
+	type plain DestinationRegion
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopy returns a deep copy of the Extra.
+func (in Extra) DeepCopy() Extra {
+	return deepCopyMap(in, func(v json.RawMessage) json.RawMessage { return slices.Clone(v) })
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *GeoEnrichment) DeepCopyInto(out *GeoEnrichment) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the GeoEnrichment.
+func (in *GeoEnrichment) DeepCopy() *GeoEnrichment {
+	if in == nil {
+		return nil
+	}
+	out := new(GeoEnrichment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the GeoEnrichment, keeping the fields it doesn't model in Extra.
+func (in *GeoEnrichment) UnmarshalJSON(data []byte) error {
+	type plain GeoEnrichment
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the GeoEnrichment, including the fields in Extra.
+func (in GeoEnrichment) MarshalJSON() ([]byte, error) {
+	type plain GeoEnrichment
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Library) DeepCopyInto(out *Library) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Library.
+func (in *Library) DeepCopy() *Library {
+	if in == nil {
+		return nil
+	}
+	out := new(Library)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Library, keeping the fields it doesn't model in Extra.
+func (in *Library) UnmarshalJSON(data []byte) error {
+	type plain Library
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Library, including the fields in Extra.
+func (in Library) MarshalJSON() ([]byte, error) {
+	type plain Library
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Resource) DeepCopyInto(out *Resource) {
+	*out = *in
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Resource.
+func (in *Resource) DeepCopy() *Resource {
+	if in == nil {
+		return nil
+	}
+	out := new(Resource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Resource, keeping the fields it doesn't model in Extra.
+func (in *Resource) UnmarshalJSON(data []byte) error {
+	type plain Resource
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Resource, including the fields in Extra.
+func (in Resource) MarshalJSON() ([]byte, error) {
+	type plain Resource
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *SQLModelVersion) DeepCopyInto(out *SQLModelVersion) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the SQLModelVersion.
+func (in *SQLModelVersion) DeepCopy() *SQLModelVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLModelVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the SQLModelVersion, keeping the fields it doesn't model in Extra.
+func (in *SQLModelVersion) UnmarshalJSON(data []byte) error {
+	type plain SQLModelVersion
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the SQLModelVersion, including the fields in Extra.
+func (in SQLModelVersion) MarshalJSON() ([]byte, error) {
+	type plain SQLModelVersion
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *SQLModelVersionSourceConnection) DeepCopyInto(out *SQLModelVersionSourceConnection) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the SQLModelVersionSourceConnection.
+func (in *SQLModelVersionSourceConnection) DeepCopy() *SQLModelVersionSourceConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLModelVersionSourceConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the SQLModelVersionSourceConnection, keeping the fields it doesn't model in Extra.
+func (in *SQLModelVersionSourceConnection) UnmarshalJSON(data []byte) error {
+	type plain SQLModelVersionSourceConnection
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the SQLModelVersionSourceConnection, including the fields in Extra.
+func (in SQLModelVersionSourceConnection) MarshalJSON() ([]byte, error) {
+	type plain SQLModelVersionSourceConnection
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Settings) DeepCopyInto(out *Settings) {
+	*out = *in
+	in.DataRetention.DeepCopyInto(&out.DataRetention)
+	in.StorageBucket.DeepCopyInto(&out.StorageBucket)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Settings.
+func (in *Settings) DeepCopy() *Settings {
+	if in == nil {
+		return nil
+	}
+	out := new(Settings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Settings, keeping the fields it doesn't model in Extra.
+func (in *Settings) UnmarshalJSON(data []byte) error {
+	type plain Settings
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Settings, including the fields in Extra.
+func (in Settings) MarshalJSON() ([]byte, error) {
+	type plain Settings
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Source) DeepCopyInto(out *Source) {
+	*out = *in
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.TrackingPlanConfig = in.TrackingPlanConfig.DeepCopy()
+	out.LiveEventsConfig = in.LiveEventsConfig.DeepCopy()
+	out.GeoEnrichment = in.GeoEnrichment.DeepCopy()
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Source.
+func (in *Source) DeepCopy() *Source {
+	if in == nil {
+		return nil
+	}
+	out := new(Source)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Source, keeping the fields it doesn't model in Extra.
+func (in *Source) UnmarshalJSON(data []byte) error {
+	type plain Source
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Source, including the fields in Extra.
+func (in Source) MarshalJSON() ([]byte, error) {
+	type plain Source
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *SourceDefinition) DeepCopyInto(out *SourceDefinition) {
+	*out = *in
+	out.Options = deepCopyMap(in.Options, deepCopyValue)
+	out.Config = deepCopyMap(in.Config, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the SourceDefinition.
+func (in *SourceDefinition) DeepCopy() *SourceDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the SourceDefinition, keeping the fields it doesn't model in Extra.
+func (in *SourceDefinition) UnmarshalJSON(data []byte) error {
+	type plain SourceDefinition
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the SourceDefinition, including the fields in Extra.
+func (in SourceDefinition) MarshalJSON() ([]byte, error) {
+	type plain SourceDefinition
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopy returns a deep copy of the SourceDefinitions.
+func (in SourceDefinitions) DeepCopy() SourceDefinitions {
+	return deepCopyMap(in, (*SourceDefinition).DeepCopy)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *SourceLiveEventsConfig) DeepCopyInto(out *SourceLiveEventsConfig) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the SourceLiveEventsConfig.
+func (in *SourceLiveEventsConfig) DeepCopy() *SourceLiveEventsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceLiveEventsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the SourceLiveEventsConfig, keeping the fields it doesn't model in Extra.
+func (in *SourceLiveEventsConfig) UnmarshalJSON(data []byte) error {
+	type plain SourceLiveEventsConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the SourceLiveEventsConfig, including the fields in Extra.
+func (in SourceLiveEventsConfig) MarshalJSON() ([]byte, error) {
+	type plain SourceLiveEventsConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *TrackingPlan) DeepCopyInto(out *TrackingPlan) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the TrackingPlan.
+func (in *TrackingPlan) DeepCopy() *TrackingPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(TrackingPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the TrackingPlan, keeping the fields it doesn't model in Extra.
+func (in *TrackingPlan) UnmarshalJSON(data []byte) error {
+	type plain TrackingPlan
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the TrackingPlan, including the fields in Extra.
+func (in TrackingPlan) MarshalJSON() ([]byte, error) {
+	type plain TrackingPlan
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Transformation) DeepCopyInto(out *Transformation) {
+	*out = *in
+	out.LiveEventsConfig = in.LiveEventsConfig.DeepCopy()
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the Transformation.
+func (in *Transformation) DeepCopy() *Transformation {
+	if in == nil {
+		return nil
+	}
+	out := new(Transformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the Transformation, keeping the fields it doesn't model in Extra.
+func (in *Transformation) UnmarshalJSON(data []byte) error {
+	type plain Transformation
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the Transformation, including the fields in Extra.
+func (in Transformation) MarshalJSON() ([]byte, error) {
+	type plain Transformation
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *TransformationLiveEventsConfig) DeepCopyInto(out *TransformationLiveEventsConfig) {
+	*out = *in
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the TransformationLiveEventsConfig.
+func (in *TransformationLiveEventsConfig) DeepCopy() *TransformationLiveEventsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformationLiveEventsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the TransformationLiveEventsConfig, keeping the fields it doesn't model in Extra.
+func (in *TransformationLiveEventsConfig) UnmarshalJSON(data []byte) error {
+	type plain TransformationLiveEventsConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the TransformationLiveEventsConfig, including the fields in Extra.
+func (in TransformationLiveEventsConfig) MarshalJSON() ([]byte, error) {
+	type plain TransformationLiveEventsConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *WHTProject) DeepCopyInto(out *WHTProject) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the WHTProject.
+func (in *WHTProject) DeepCopy() *WHTProject {
+	if in == nil {
+		return nil
+	}
+	out := new(WHTProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *WHTProjectConfig) DeepCopyInto(out *WHTProjectConfig) {
+	*out = *in
+	out.Resources = slices.Clone(in.Resources)
+	out.Schedule = deepCopyMap(in.Schedule, deepCopyValue)
+	out.RetentionPolicy = deepCopyMap(in.RetentionPolicy, deepCopyValue)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the WHTProjectConfig.
+func (in *WHTProjectConfig) DeepCopy() *WHTProjectConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WHTProjectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the WHTProjectConfig, keeping the fields it doesn't model in Extra.
+func (in *WHTProjectConfig) UnmarshalJSON(data []byte) error {
+	type plain WHTProjectConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the WHTProjectConfig, including the fields in Extra.
+func (in WHTProjectConfig) MarshalJSON() ([]byte, error) {
+	type plain WHTProjectConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
+	*out = *in
+	out.Settings = in.Settings.DeepCopy()
+	out.Sources = deepCopyMap(in.Sources, (*Source).DeepCopy)
+	out.Destinations = deepCopyMap(in.Destinations, (*Destination).DeepCopy)
+	out.Connections = deepCopyMap(in.Connections, (*Connection).DeepCopy)
+	out.Libraries = deepCopySlice(in.Libraries, (*Library).DeepCopy)
+	out.WHTProjects = deepCopyMap(in.WHTProjects, (*WHTProject).DeepCopy)
+	out.Accounts = deepCopyMap(in.Accounts, (*Account).DeepCopy)
+	out.Transformations = deepCopyMap(in.Transformations, (*Transformation).DeepCopy)
+	out.TrackingPlans = deepCopyMap(in.TrackingPlans, (*TrackingPlan).DeepCopy)
+	out.Resources = deepCopyMap(in.Resources, (*Resource).DeepCopy)
+	out.AudienceSources = deepCopySlice(in.AudienceSources, (*AudienceSource).DeepCopy)
+	out.SQLModelVersions = deepCopyMap(in.SQLModelVersions, (*SQLModelVersion).DeepCopy)
+	out.SQLModelVersionSourceConnections = deepCopySlice(in.SQLModelVersionSourceConnections, (*SQLModelVersionSourceConnection).DeepCopy)
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the WorkspaceConfig.
+func (in *WorkspaceConfig) DeepCopy() *WorkspaceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the WorkspaceConfig, keeping the fields it doesn't model in Extra.
+func (in *WorkspaceConfig) UnmarshalJSON(data []byte) error {
+	type plain WorkspaceConfig
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the WorkspaceConfig, including the fields in Extra.
+func (in WorkspaceConfig) MarshalJSON() ([]byte, error) {
+	type plain WorkspaceConfig
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *WorkspaceConfigs) DeepCopyInto(out *WorkspaceConfigs) {
+	*out = *in
+	out.Workspaces = in.Workspaces.DeepCopy()
+	out.SourceDefinitions = in.SourceDefinitions.DeepCopy()
+	out.DestinationDefinitions = in.DestinationDefinitions.DeepCopy()
+	out.Extra = in.Extra.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the WorkspaceConfigs.
+func (in *WorkspaceConfigs) DeepCopy() *WorkspaceConfigs {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceConfigs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// UnmarshalJSON decodes the WorkspaceConfigs, keeping the fields it doesn't model in Extra.
+func (in *WorkspaceConfigs) UnmarshalJSON(data []byte) error {
+	type plain WorkspaceConfigs
+	return unmarshalWithExtra(data, (*plain)(in), &in.Extra)
+}
+
+// MarshalJSON encodes the WorkspaceConfigs, including the fields in Extra.
+func (in WorkspaceConfigs) MarshalJSON() ([]byte, error) {
+	type plain WorkspaceConfigs
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// DeepCopy returns a deep copy of the Workspaces.
+func (in Workspaces) DeepCopy() Workspaces {
+	return deepCopyMap(in, (*WorkspaceConfig).DeepCopy)
+}
@@ -0,0 +1,218 @@
+package modelv2
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/rudderlabs/rudder-cp-sdk/internal/redact"
+)
+
+// RedactedValue replaces the values of redacted secrets.
+const RedactedValue = redact.Value
+
+// IsSecretKey returns true if a config key looks like it holds a secret, e.g. "password" or "api_key".
+func IsSecretKey(key string) bool {
+	return redact.IsSecretKey(key)
+}
+
+// RedactConfig returns a deep copy of a config, with the values of secret looking keys (see [IsSecretKey]) and of
+// the given top level secret keys replaced by [RedactedValue].
+func RedactConfig(config map[string]any, secretKeys ...string) map[string]any {
+	return redact.Config(config, secretKeys...)
+}
+
+// RedactJSON redacts the secret looking values of a JSON document, e.g. the body of an error response.
+// Documents without secrets are returned as they are, and documents that cannot be parsed are redacted on a best
+// effort basis.
+func RedactJSON(data []byte) []byte {
+	return redact.JSON(data)
+}
+
+// Redacted returns a deep copy of the account with the values of its secret and the secret looking values of its
+// options, metadata and extra fields replaced by [RedactedValue].
+func (a *Account) Redacted() *Account {
+	redacted := *a
+	redacted.Options = RedactConfig(a.Options)
+	redacted.Metadata = RedactConfig(a.Metadata)
+	redacted.Extra = a.Extra.Redacted()
+	if a.Secret != nil {
+		redacted.Secret = make(map[string]any, len(a.Secret))
+		for key := range a.Secret {
+			redacted.Secret[key] = RedactedValue
+		}
+	}
+	return &redacted
+}
+
+// Redacted returns a deep copy of the source with its write key and the secret looking values of its config and
+// extra fields replaced by [RedactedValue]. Use [Source.RedactedFor] to also redact the secret keys of its definition.
+func (s *Source) Redacted() *Source {
+	return s.RedactedFor(nil)
+}
+
+// RedactedFor is like [Source.Redacted], but also redacts the config keys listed by the secretKeys of def, if any.
+func (s *Source) RedactedFor(def *SourceDefinition) *Source {
+	redacted := *s
+	if s.WriteKey != "" {
+		redacted.WriteKey = RedactedValue
+	}
+	redacted.Config = RedactConfig(s.Config, definitionSecretKeys(def)...)
+	redacted.Extra = s.Extra.Redacted()
+	if s.TrackingPlanConfig != nil {
+		tp := *s.TrackingPlanConfig
+		redacted.TrackingPlanConfig = &tp
+	}
+	return &redacted
+}
+
+// Redacted returns a deep copy of the destination with the secret looking values of its config and extra fields,
+// and of those of its regions, replaced by [RedactedValue]. Use [Destination.RedactedFor] to also redact the secret
+// keys of its definition.
+func (d *Destination) Redacted() *Destination {
+	return d.RedactedFor(nil)
+}
+
+// RedactedFor is like [Destination.Redacted], but also redacts the config keys listed by the secretKeys of def, if
+// any.
+func (d *Destination) RedactedFor(def *DestinationDefinition) *Destination {
+	var secretKeys []string
+	if def != nil {
+		secretKeys = stringValues(def.Config["secretKeys"])
+	}
+	redacted := *d
+	redacted.Config = RedactConfig(d.Config, secretKeys...)
+	redacted.Extra = d.Extra.Redacted()
+	redacted.TransformationIDs = slices.Clone(d.TransformationIDs)
+	if d.Regions != nil {
+		redacted.Regions = make([]*DestinationRegion, len(d.Regions))
+		for i, r := range d.Regions {
+			if r == nil {
+				continue
+			}
+			region := *r
+			region.Config = RedactConfig(r.Config, secretKeys...)
+			region.Extra = r.Extra.Redacted()
+			redacted.Regions[i] = &region
+		}
+	}
+	return &redacted
+}
+
+// Redacted returns a copy of the workspace configs where accounts, sources, destinations, resources, storage
+// buckets and the extra fields of workspaces (see [Extra]) are redacted, using the secret keys of their definitions
+// where available. Entities that hold no secrets are shared with wcs.
+func (wcs *WorkspaceConfigs) Redacted() *WorkspaceConfigs {
+	redacted := *wcs
+	redacted.Extra = wcs.Extra.Redacted()
+	if wcs.Workspaces != nil {
+		redacted.Workspaces = make(Workspaces, len(wcs.Workspaces))
+		for workspaceID, wc := range wcs.Workspaces {
+			redacted.Workspaces[workspaceID] = wc.redacted(wcs)
+		}
+	}
+	return &redacted
+}
+
+func (wc *WorkspaceConfig) redacted(wcs *WorkspaceConfigs) *WorkspaceConfig {
+	if wc == nil {
+		return nil
+	}
+	redacted := *wc
+	redacted.Extra = wc.Extra.Redacted()
+	if wc.Settings != nil {
+		settings := *wc.Settings
+		settings.StorageBucket.Config = RedactConfig(settings.StorageBucket.Config)
+		settings.StorageBucket.Extra = settings.StorageBucket.Extra.Redacted()
+		settings.DataRetention.StorageBucket.Config = RedactConfig(settings.DataRetention.StorageBucket.Config)
+		settings.DataRetention.StorageBucket.Extra = settings.DataRetention.StorageBucket.Extra.Redacted()
+		redacted.Settings = &settings
+	}
+	redacted.Sources = redactMap(wc.Sources, func(s *Source) *Source {
+		return s.RedactedFor(wcs.SourceDefinitions[s.DefinitionName])
+	})
+	redacted.Destinations = redactMap(wc.Destinations, func(d *Destination) *Destination {
+		return d.RedactedFor(wcs.DestinationDefinitions[d.DefinitionName])
+	})
+	redacted.Accounts = redactMap(wc.Accounts, (*Account).Redacted)
+	redacted.Resources = redactMap(wc.Resources, func(r *Resource) *Resource {
+		resource := *r
+		resource.Config = RedactConfig(r.Config)
+		resource.Extra = r.Extra.Redacted()
+		return &resource
+	})
+	return &redacted
+}
+
+func redactMap[V any](m map[string]*V, redact func(*V) *V) map[string]*V {
+	if m == nil {
+		return nil
+	}
+	redacted := maps.Clone(m)
+	for key, v := range m {
+		if v != nil {
+			redacted[key] = redact(v)
+		}
+	}
+	return redacted
+}
+
+func definitionSecretKeys(def *SourceDefinition) []string {
+	if def == nil {
+		return nil
+	}
+	return stringValues(def.Config["secretKeys"])
+}
+
+func stringValues(v any) []string {
+	items, _ := v.([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// The types below have the same fields as the redacted ones, but none of their methods, so that formatting them
+// doesn't recurse into the Format methods.
+type (
+	plainAccount     Account
+	plainSource      Source
+	plainDestination Destination
+)
+
+// Format formats the account with its secrets redacted, see [Account.Redacted].
+func (a Account) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), plainAccount(*a.Redacted()))
+}
+
+// String returns the account with its secrets redacted, see [Account.Redacted].
+func (a Account) String() string { return fmt.Sprint(a) }
+
+// LogValue logs the account with its secrets redacted, see [Account.Redacted].
+func (a Account) LogValue() slog.Value { return slog.AnyValue(plainAccount(*a.Redacted())) }
+
+// Format formats the source with its secrets redacted, see [Source.Redacted].
+func (s Source) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), plainSource(*s.Redacted()))
+}
+
+// String returns the source with its secrets redacted, see [Source.Redacted].
+func (s Source) String() string { return fmt.Sprint(s) }
+
+// LogValue logs the source with its secrets redacted, see [Source.Redacted].
+func (s Source) LogValue() slog.Value { return slog.AnyValue(plainSource(*s.Redacted())) }
+
+// Format formats the destination with its secrets redacted, see [Destination.Redacted].
+func (d Destination) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), plainDestination(*d.Redacted()))
+}
+
+// String returns the destination with its secrets redacted, see [Destination.Redacted].
+func (d Destination) String() string { return fmt.Sprint(d) }
+
+// LogValue logs the destination with its secrets redacted, see [Destination.Redacted].
+func (d Destination) LogValue() slog.Value { return slog.AnyValue(plainDestination(*d.Redacted())) }
@@ -0,0 +1,101 @@
+package modelv2_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestRedaction(t *testing.T) {
+	secrets := []string{"some-write-key-for-source-1", "some-fancy-token", "some-access-token"}
+	load := func(t *testing.T) *modelv2.WorkspaceConfigs {
+		t.Helper()
+		data, err := os.ReadFile("../testdata/sample_namespace.json")
+		require.NoError(t, err)
+		wcs := &modelv2.WorkspaceConfigs{}
+		require.NoError(t, jsonrs.Unmarshal(data, wcs))
+		return wcs
+	}
+
+	t.Run("formatting", func(t *testing.T) {
+		wcs := load(t)
+		account := wcs.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"].Accounts["2gViSzlt6hyqKmPsrdkFaC1gxke"]
+		source := wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Sources["2hCDcJJGtZCIMDWjAyjsOg0W1Hr"]
+		destination := wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Destinations["2i9lmpOsrP9KHqDR5hAlKWfNzb5"]
+
+		var logs bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&logs, nil))
+		for _, v := range []any{account, *account, source, *source, destination, *destination} {
+			for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+				formatted := fmt.Sprintf(format, v)
+				require.Contains(t, formatted, modelv2.RedactedValue, format)
+				for _, secret := range secrets {
+					require.NotContains(t, formatted, secret, format)
+				}
+			}
+			log.Info("entity", "value", v)
+		}
+		require.Contains(t, account.String(), modelv2.RedactedValue)
+		require.Contains(t, source.String(), modelv2.RedactedValue)
+		require.Contains(t, destination.String(), modelv2.RedactedValue)
+
+		require.Contains(t, logs.String(), modelv2.RedactedValue)
+		for _, secret := range secrets {
+			require.NotContains(t, logs.String(), secret)
+		}
+
+		require.Equal(t, "some-access-token", account.Secret["access_token"], "the original should not be modified")
+		require.Equal(t, "some-write-key-for-source-1", source.WriteKey)
+		require.Equal(t, "some-fancy-token", destination.Config["token"])
+	})
+
+	t.Run("workspace configs", func(t *testing.T) {
+		wcs := load(t)
+		original := load(t)
+
+		wcs.DestinationDefinitions["MP"] = &modelv2.DestinationDefinition{
+			Config: map[string]any{"secretKeys": []any{"apiSecret", "signingCode"}},
+		}
+		wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Destinations["2i9lmpOsrP9KHqDR5hAlKWfNzb5"].Config["signingCode"] = "code"
+
+		redacted := wcs.Redacted()
+		data, err := jsonrs.Marshal(redacted)
+		require.NoError(t, err)
+		for _, secret := range secrets {
+			require.NotContains(t, string(data), secret)
+		}
+		mp := redacted.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Destinations["2i9lmpOsrP9KHqDR5hAlKWfNzb5"]
+		require.Equal(t, modelv2.RedactedValue, mp.Config["signingCode"], "secret keys of the definition should be redacted")
+		require.Equal(t, modelv2.RedactedValue, mp.Config["token"])
+		bq := redacted.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Destinations["2hQdZuO7jPzgcw1uN9NaU1u2wPg"]
+		require.Equal(t, "a-very-cool-project", bq.Config["project"], "non secret values should be kept")
+
+		delete(wcs.DestinationDefinitions, "MP")
+		delete(wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"].Destinations["2i9lmpOsrP9KHqDR5hAlKWfNzb5"].Config, "signingCode")
+		require.Equal(t, original, wcs, "the original should not be modified")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		require.JSONEq(t,
+			`{"error":"unauthorized","details":{"apiKey":"[REDACTED]","items":[{"password":"[REDACTED]","user":"me"}]}}`,
+			string(modelv2.RedactJSON([]byte(`{"error":"unauthorized","details":{"apiKey":"k","items":[{"password":"p","user":"me"}]}}`))),
+		)
+		require.Equal(t,
+			`{"user":"me","access_token":"[REDACTED]","writeKey":"[REDACTED]","truncat`,
+			string(modelv2.RedactJSON([]byte(`{"user":"me","access_token":"abc\"def","writeKey":"wk","truncat`))),
+		)
+		require.Equal(t, "internal server error", string(modelv2.RedactJSON([]byte("internal server error"))))
+
+		// documents without secrets are returned as they are, rather than encoded again
+		body := []byte(`{ "message": "not found",  "code": 404, "details": {"b": 1.50, "a": null, "token": null} }`)
+		require.Equal(t, string(body), string(modelv2.RedactJSON(body)))
+	})
+}
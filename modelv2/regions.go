@@ -6,3 +6,18 @@ const (
 	RegionUS Region = "US"
 	RegionEU Region = "EU"
 )
+
+// ProjectToRegion replaces all the destinations of wcs with their [Destination.Effective] version for the given
+// region, so that the settings of other regions are dropped. Workspaces that are nil (i.e. not updated) are skipped.
+func (wcs *WorkspaceConfigs) ProjectToRegion(region Region) {
+	for _, wc := range wcs.Workspaces {
+		if wc == nil {
+			continue
+		}
+		for id, destination := range wc.Destinations {
+			if destination != nil {
+				wc.Destinations[id] = destination.Effective(region)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package modelv2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestDestinationEffective(t *testing.T) {
+	newDestination := func() *modelv2.Destination {
+		return &modelv2.Destination{
+			Name: "Destination",
+			Config: map[string]any{
+				"endpoint": "us.example.com",
+				"apiKey":   "key",
+				"mapping":  []any{"a", "b"},
+				"connectionMode": map[string]any{
+					"web":    "cloud",
+					"mobile": "device",
+				},
+			},
+			RevisionID:    "rev-1",
+			SecretVersion: 2,
+			Regions: []*modelv2.DestinationRegion{
+				{
+					Region: modelv2.RegionEU,
+					Config: map[string]any{
+						"endpoint":       "eu.example.com",
+						"apiKey":         nil,
+						"mapping":        []any{"c"},
+						"connectionMode": map[string]any{"web": "device"},
+					},
+					RevisionID: "rev-eu-1",
+				},
+			},
+		}
+	}
+
+	t.Run("with overrides", func(t *testing.T) {
+		d := newDestination()
+		effective := d.Effective(modelv2.RegionEU)
+		require.Equal(t, &modelv2.Destination{
+			Name: "Destination",
+			Config: map[string]any{
+				"endpoint": "eu.example.com",
+				"mapping":  []any{"c"},
+				"connectionMode": map[string]any{
+					"web":    "device",
+					"mobile": "device",
+				},
+			},
+			RevisionID:    "rev-eu-1",
+			SecretVersion: 2,
+		}, effective)
+		require.Equal(t, newDestination(), d, "the original destination should not be modified")
+	})
+
+	t.Run("without overrides", func(t *testing.T) {
+		d := newDestination()
+		effective := d.Effective(modelv2.RegionUS)
+		expected := newDestination()
+		expected.Regions = nil
+		require.Equal(t, expected, effective)
+	})
+
+	t.Run("project workspace configs", func(t *testing.T) {
+		wcs := &modelv2.WorkspaceConfigs{Workspaces: map[string]*modelv2.WorkspaceConfig{
+			"ws-1": {Destinations: map[string]*modelv2.Destination{"dst-1": newDestination(), "dst-2": nil}},
+			"ws-2": nil,
+		}}
+		wcs.ProjectToRegion(modelv2.RegionEU)
+		require.Equal(t, newDestination().Effective(modelv2.RegionEU), wcs.Workspaces["ws-1"].Destinations["dst-1"])
+		require.Nil(t, wcs.Workspaces["ws-1"].Destinations["dst-2"])
+		require.Nil(t, wcs.Workspaces["ws-2"])
+	})
+}
@@ -4,4 +4,5 @@ type Resource struct {
 	Name   string         `json:"name"`
 	Role   string         `json:"role"`
 	Config map[string]any `json:"config"`
+	Extra  Extra          `json:"-"`
 }
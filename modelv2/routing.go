@@ -0,0 +1,120 @@
+package modelv2
+
+import (
+	"slices"
+	"strings"
+)
+
+// RoutingGraph is the routing table of a workspace: which destinations the events of each source are delivered to.
+type RoutingGraph struct {
+	// Sources maps the ID of each enabled, non deleted source to its routes.
+	Sources map[string]*SourceRoutes
+}
+
+// SourceRoutes are the routes of a source, split by whether events go through the processor or not.
+// Both lists are sorted by destination ID, then by connection ID.
+type SourceRoutes struct {
+	SourceID     string
+	Source       *Source
+	Processor    []Route
+	NonProcessor []Route
+}
+
+// Route is an effective path from a source to a destination.
+type Route struct {
+	ConnectionID  string
+	DestinationID string
+	Destination   *Destination
+	// Transformations is the chain of transformations applied to the events before they reach the destination, in the
+	// order of Destination.TransformationIDs.
+	Transformations []RouteTransformation
+}
+
+// RouteTransformation is a transformation of a route, resolved against WorkspaceConfig.Transformations.
+type RouteTransformation struct {
+	TransformationID string
+	// VersionID is the version of the transformation, empty if the transformation is not part of the workspace.
+	VersionID string
+}
+
+// NewRoutingGraph builds the routing graph of a workspace from its connections. Connections are skipped if they are
+// disabled, or if their source or destination is missing, disabled or deleted.
+func NewRoutingGraph(wc *WorkspaceConfig) *RoutingGraph {
+	g := &RoutingGraph{Sources: make(map[string]*SourceRoutes)}
+	if wc == nil {
+		return g
+	}
+	for sourceID, source := range wc.Sources {
+		if source == nil || !source.Enabled || source.Deleted {
+			continue
+		}
+		g.Sources[sourceID] = &SourceRoutes{SourceID: sourceID, Source: source}
+	}
+	for connectionID, conn := range wc.Connections {
+		if conn == nil || !conn.Enabled {
+			continue
+		}
+		sr, ok := g.Sources[conn.SourceID]
+		if !ok {
+			continue
+		}
+		destination := wc.Destinations[conn.DestinationID]
+		if destination == nil || !destination.Enabled || destination.Deleted {
+			continue
+		}
+		route := Route{
+			ConnectionID:    connectionID,
+			DestinationID:   conn.DestinationID,
+			Destination:     destination,
+			Transformations: resolveTransformations(wc, destination.TransformationIDs),
+		}
+		if conn.ProcessorEnabled {
+			sr.Processor = append(sr.Processor, route)
+		} else {
+			sr.NonProcessor = append(sr.NonProcessor, route)
+		}
+	}
+	for _, sr := range g.Sources {
+		slices.SortFunc(sr.Processor, compareRoutes)
+		slices.SortFunc(sr.NonProcessor, compareRoutes)
+	}
+	return g
+}
+
+// Routes returns the routes of the source with the given ID, if it is enabled and not deleted.
+func (g *RoutingGraph) Routes(sourceID string) (*SourceRoutes, bool) {
+	sr, ok := g.Sources[sourceID]
+	return sr, ok
+}
+
+// SourceIDs returns the IDs of the routed sources, sorted.
+func (g *RoutingGraph) SourceIDs() []string {
+	ids := make([]string, 0, len(g.Sources))
+	for id := range g.Sources {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func resolveTransformations(wc *WorkspaceConfig, ids []string) []RouteTransformation {
+	if len(ids) == 0 {
+		return nil
+	}
+	transformations := make([]RouteTransformation, 0, len(ids))
+	for _, id := range ids {
+		rt := RouteTransformation{TransformationID: id}
+		if t := wc.Transformations[id]; t != nil {
+			rt.VersionID = t.VersionID
+		}
+		transformations = append(transformations, rt)
+	}
+	return transformations
+}
+
+func compareRoutes(a, b Route) int {
+	if c := strings.Compare(a.DestinationID, b.DestinationID); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ConnectionID, b.ConnectionID)
+}
@@ -0,0 +1,112 @@
+package modelv2_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestRoutingGraph(t *testing.T) {
+	t.Run("sample namespace", func(t *testing.T) {
+		data, err := os.ReadFile("../testdata/sample_namespace.json")
+		require.NoError(t, err)
+		wcs := &modelv2.WorkspaceConfigs{}
+		require.NoError(t, jsonrs.Unmarshal(data, wcs))
+
+		wc := wcs.Workspaces["2hCBi02C8xYS8Rsy1m9bJjTlKy6"]
+		g := modelv2.NewRoutingGraph(wc)
+		require.Equal(t, []string{"2hCDcJJGtZCIMDWjAyjsOg0W1Hr"}, g.SourceIDs())
+		sr, ok := g.Routes("2hCDcJJGtZCIMDWjAyjsOg0W1Hr")
+		require.True(t, ok)
+		require.Same(t, wc.Sources["2hCDcJJGtZCIMDWjAyjsOg0W1Hr"], sr.Source)
+		require.Empty(t, sr.NonProcessor)
+		require.Equal(t, []modelv2.Route{
+			{
+				ConnectionID:  "2hQdZtIleMfPqvozvCYtGDdjDYa",
+				DestinationID: "2hQdZuO7jPzgcw1uN9NaU1u2wPg",
+				Destination:   wc.Destinations["2hQdZuO7jPzgcw1uN9NaU1u2wPg"],
+			},
+			{
+				ConnectionID:  "2i9lmuu9TW8vT4YJ0rTTSMDtVJR",
+				DestinationID: "2i9lmpOsrP9KHqDR5hAlKWfNzb5",
+				Destination:   wc.Destinations["2i9lmpOsrP9KHqDR5hAlKWfNzb5"],
+			},
+		}, sr.Processor)
+
+		// the only destination of the second workspace is disabled
+		g = modelv2.NewRoutingGraph(wcs.Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"])
+		sr, ok = g.Routes("2jIY89R3Ul5PxVsynuYapfmgtfP")
+		require.True(t, ok)
+		require.Empty(t, sr.Processor)
+		require.Empty(t, sr.NonProcessor)
+	})
+
+	t.Run("skipped entities and transformations", func(t *testing.T) {
+		wc := &modelv2.WorkspaceConfig{
+			Sources: map[string]*modelv2.Source{
+				"src-1":      {Enabled: true},
+				"src-2":      {Enabled: true},
+				"disabled":   {Enabled: false},
+				"deleted":    {Enabled: true, Deleted: true},
+				"no-targets": {Enabled: true},
+			},
+			Destinations: map[string]*modelv2.Destination{
+				"dst-1":    {Enabled: true, TransformationIDs: []string{"tr-2", "tr-1", "tr-unknown"}},
+				"dst-2":    {Enabled: true},
+				"disabled": {Enabled: false},
+				"deleted":  {Enabled: true, Deleted: true},
+			},
+			Connections: map[string]*modelv2.Connection{
+				"conn-1": {SourceID: "src-1", DestinationID: "dst-1", Enabled: true, ProcessorEnabled: true},
+				"conn-2": {SourceID: "src-1", DestinationID: "dst-2", Enabled: true, ProcessorEnabled: false},
+				"conn-3": {SourceID: "src-2", DestinationID: "dst-1", Enabled: false, ProcessorEnabled: true},
+				"conn-4": {SourceID: "src-2", DestinationID: "disabled", Enabled: true, ProcessorEnabled: true},
+				"conn-5": {SourceID: "src-2", DestinationID: "deleted", Enabled: true, ProcessorEnabled: true},
+				"conn-6": {SourceID: "src-2", DestinationID: "missing", Enabled: true, ProcessorEnabled: true},
+				"conn-7": {SourceID: "disabled", DestinationID: "dst-1", Enabled: true, ProcessorEnabled: true},
+				"conn-8": {SourceID: "deleted", DestinationID: "dst-1", Enabled: true, ProcessorEnabled: true},
+			},
+			Transformations: map[string]*modelv2.Transformation{
+				"tr-1": {VersionID: "v-1"},
+				"tr-2": {VersionID: "v-2"},
+			},
+		}
+
+		g := modelv2.NewRoutingGraph(wc)
+		require.Equal(t, []string{"no-targets", "src-1", "src-2"}, g.SourceIDs())
+		_, ok := g.Routes("disabled")
+		require.False(t, ok)
+		_, ok = g.Routes("deleted")
+		require.False(t, ok)
+
+		sr, _ := g.Routes("src-1")
+		require.Equal(t, []modelv2.Route{{
+			ConnectionID:  "conn-1",
+			DestinationID: "dst-1",
+			Destination:   wc.Destinations["dst-1"],
+			Transformations: []modelv2.RouteTransformation{
+				{TransformationID: "tr-2", VersionID: "v-2"},
+				{TransformationID: "tr-1", VersionID: "v-1"},
+				{TransformationID: "tr-unknown"},
+			},
+		}}, sr.Processor)
+		require.Equal(t, []modelv2.Route{{
+			ConnectionID:  "conn-2",
+			DestinationID: "dst-2",
+			Destination:   wc.Destinations["dst-2"],
+		}}, sr.NonProcessor)
+
+		sr, _ = g.Routes("src-2")
+		require.Empty(t, sr.Processor)
+		require.Empty(t, sr.NonProcessor)
+	})
+
+	t.Run("nil workspace", func(t *testing.T) {
+		require.Empty(t, modelv2.NewRoutingGraph(nil).SourceIDs())
+	})
+}
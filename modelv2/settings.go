@@ -1,30 +1,115 @@
 package modelv2
 
+import (
+	"errors"
+	"fmt"
+)
+
+// DataRetentionStoragePreferences tells which kinds of data are stored, in the storage bucket of the workspace if
+// self storage is used, or in the default storage of the data plane otherwise.
 type DataRetentionStoragePreferences struct {
-	ProcErrors   bool `json:"procErrors"`
-	GatewayDumps bool `json:"gatewayDumps"`
+	// ProcErrors tells whether the events failing in the processor are stored.
+	ProcErrors bool `json:"procErrors"`
+	// GatewayDumps tells whether the events received by the gateway are backed up.
+	GatewayDumps bool  `json:"gatewayDumps"`
+	Extra        Extra `json:"-"`
 }
 
 type DataRetention struct {
-	DisableReportingPII bool                            `json:"disableReportingPii"`
-	StoragePreferences  DataRetentionStoragePreferences `json:"storagePreferences"`
+	DisableReportingPII bool `json:"disableReportingPii"`
+	// UseSelfStorage tells whether data is stored in StorageBucket instead of the default storage of the data plane.
+	UseSelfStorage bool `json:"useSelfStorage"`
+	// RetentionPeriod tells for how long stored data is kept.
+	RetentionPeriod    RetentionPeriod                 `json:"retentionPeriod"`
+	StorageBucket      Bucket                          `json:"storageBucket"`
+	StoragePreferences DataRetentionStoragePreferences `json:"storagePreferences"`
+	Extra              Extra                           `json:"-"`
 }
 
+// Bucket is an object storage bucket. Use [Bucket.TypedConfig] to decode its config according to its type.
 type Bucket struct {
-	Type   string         `json:"type"`
+	Type   BucketType     `json:"type"`
 	Config map[string]any `json:"config"`
+	Extra  Extra          `json:"-"`
 }
 
 type Settings struct {
-	DataRetention   DataRetention   `json:"dataRetention"`
-	UseSelfStorage  bool            `json:"useSelfStorage"`
-	StorageBucket   Bucket          `json:"storageBucket"`
+	DataRetention DataRetention `json:"dataRetention"`
+	// Deprecated: use DataRetention.UseSelfStorage, which is where the control plane sends it. It is only used by
+	// [Settings.EffectiveStorage] if the data retention settings don't enable self storage.
+	UseSelfStorage bool `json:"useSelfStorage"`
+	// Deprecated: use DataRetention.StorageBucket, which is where the control plane sends it. It is only used by
+	// [Settings.EffectiveStorage] if the data retention settings have no storage bucket.
+	StorageBucket Bucket `json:"storageBucket"`
+	// Deprecated: use DataRetention.RetentionPeriod, which is where the control plane sends it. It is only used by
+	// [Settings.EffectiveStorage] if the data retention settings have no retention period.
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod"`
+	Extra           Extra           `json:"-"`
 }
 
+// RetentionPeriod tells for how long stored data is kept.
 type RetentionPeriod string
 
 const (
+	// RetentionPeriodDefault keeps data for the default retention period of the data plane.
 	RetentionPeriodDefault RetentionPeriod = "default"
-	RetentionPeriodFull    RetentionPeriod = "full"
+	// RetentionPeriodFull keeps data until it is deleted from the storage bucket.
+	RetentionPeriodFull RetentionPeriod = "full"
 )
+
+// ErrNoStorageBucket is returned by [Settings.EffectiveStorage] when self storage is enabled without a storage bucket.
+var ErrNoStorageBucket = errors.New("self storage enabled without a storage bucket")
+
+// StorageSettings are the effective storage settings of a workspace, see [Settings.EffectiveStorage].
+type StorageSettings struct {
+	// Bucket is the storage bucket of the workspace, or nil if data is stored in the default storage of the data
+	// plane.
+	Bucket *Bucket
+	// BucketConfig is the validated typed config of Bucket, if any.
+	BucketConfig BucketConfig
+	// ProcErrors tells whether the events failing in the processor are stored.
+	ProcErrors bool
+	// GatewayDumps tells whether the events received by the gateway are backed up.
+	GatewayDumps bool
+	// RetentionPeriod is never empty, defaulting to [RetentionPeriodDefault].
+	RetentionPeriod RetentionPeriod
+}
+
+// EffectiveStorage returns where and what data of the workspace is stored, according to its data retention settings.
+// If self storage is enabled, the storage bucket of the workspace is used, and an error is returned if it is missing
+// ([ErrNoStorageBucket]) or its config is invalid (e.g. [*BucketConfigError]). Nil settings are treated as empty.
+func (s *Settings) EffectiveStorage() (*StorageSettings, error) {
+	var settings Settings
+	if s != nil {
+		settings = *s
+	}
+	dr := settings.DataRetention
+	storage := &StorageSettings{
+		ProcErrors:      dr.StoragePreferences.ProcErrors,
+		GatewayDumps:    dr.StoragePreferences.GatewayDumps,
+		RetentionPeriod: dr.RetentionPeriod,
+	}
+	if storage.RetentionPeriod == "" {
+		storage.RetentionPeriod = settings.RetentionPeriod
+	}
+	if storage.RetentionPeriod == "" {
+		storage.RetentionPeriod = RetentionPeriodDefault
+	}
+	if !dr.UseSelfStorage && !settings.UseSelfStorage {
+		return storage, nil
+	}
+	bucket := dr.StorageBucket
+	if bucket.Type == "" {
+		bucket = settings.StorageBucket
+	}
+	if bucket.Type == "" {
+		return nil, ErrNoStorageBucket
+	}
+	config, err := bucket.ValidConfig()
+	if err != nil {
+		return nil, fmt.Errorf("storage bucket: %w", err)
+	}
+	storage.Bucket = bucket.DeepCopy()
+	storage.BucketConfig = config
+	return storage, nil
+}
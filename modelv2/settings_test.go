@@ -0,0 +1,169 @@
+package modelv2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestSettings(t *testing.T) {
+	t.Run("data retention", func(t *testing.T) {
+		settings := sampleWorkspaceConfigs(t).Workspaces["2bVMV2JiAJe42OXZrzyvJI75v0N"].Settings
+		require.NotNil(t, settings)
+		require.Equal(t, modelv2.RetentionPeriodDefault, settings.DataRetention.RetentionPeriod)
+		require.NotContains(t, settings.DataRetention.Extra, "useSelfStorage")
+		require.NotContains(t, settings.DataRetention.Extra, "retentionPeriod")
+
+		storage, err := settings.EffectiveStorage()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.StorageSettings{
+			ProcErrors:      true,
+			GatewayDumps:    true,
+			RetentionPeriod: modelv2.RetentionPeriodDefault,
+		}, storage)
+	})
+
+	t.Run("self storage", func(t *testing.T) {
+		var settings modelv2.Settings
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{
+			"dataRetention": {
+				"useSelfStorage": true,
+				"retentionPeriod": "full",
+				"storageBucket": {
+					"type": "S3",
+					"config": {"bucketName": "backups", "prefix": "rudder", "roleBasedAuth": true, "iamRoleARN": "arn:aws:iam::123456789012:role/rudder"}
+				},
+				"storagePreferences": {"procErrors": true, "gatewayDumps": false}
+			}
+		}`), &settings))
+
+		storage, err := settings.EffectiveStorage()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.StorageSettings{
+			Bucket: &settings.DataRetention.StorageBucket,
+			BucketConfig: &modelv2.S3BucketConfig{
+				BucketName:    "backups",
+				Prefix:        "rudder",
+				RoleBasedAuth: true,
+				IAMRoleARN:    "arn:aws:iam::123456789012:role/rudder",
+			},
+			ProcErrors:      true,
+			RetentionPeriod: modelv2.RetentionPeriodFull,
+		}, storage)
+		require.NotSame(t, &settings.DataRetention.StorageBucket, storage.Bucket)
+	})
+
+	t.Run("deprecated settings", func(t *testing.T) {
+		settings := &modelv2.Settings{
+			UseSelfStorage:  true,
+			RetentionPeriod: modelv2.RetentionPeriodFull,
+			StorageBucket: modelv2.Bucket{
+				Type:   modelv2.BucketTypeGCS,
+				Config: map[string]any{"bucketName": "backups", "credentials": "{}"},
+			},
+		}
+		storage, err := settings.EffectiveStorage()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.GCSBucketConfig{BucketName: "backups", Credentials: "{}"}, storage.BucketConfig)
+		require.Equal(t, modelv2.RetentionPeriodFull, storage.RetentionPeriod)
+	})
+
+	t.Run("nil settings", func(t *testing.T) {
+		var settings *modelv2.Settings
+		storage, err := settings.EffectiveStorage()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.StorageSettings{RetentionPeriod: modelv2.RetentionPeriodDefault}, storage)
+	})
+
+	t.Run("invalid self storage", func(t *testing.T) {
+		settings := &modelv2.Settings{DataRetention: modelv2.DataRetention{UseSelfStorage: true}}
+		_, err := settings.EffectiveStorage()
+		require.ErrorIs(t, err, modelv2.ErrNoStorageBucket)
+
+		settings.DataRetention.StorageBucket = modelv2.Bucket{Type: modelv2.BucketTypeAzureBlob, Config: map[string]any{"containerName": "backups"}}
+		_, err = settings.EffectiveStorage()
+		var configErr *modelv2.BucketConfigError
+		require.ErrorAs(t, err, &configErr)
+		require.EqualError(t, err, "storage bucket: AZURE_BLOB bucket config is missing accountName, accountKey")
+
+		settings.DataRetention.StorageBucket = modelv2.Bucket{Type: "FTP"}
+		_, err = settings.EffectiveStorage()
+		require.EqualError(t, err, `storage bucket: unsupported bucket type "FTP"`)
+
+		settings.DataRetention.StorageBucket = modelv2.Bucket{Type: modelv2.BucketTypeMinIO, Config: map[string]any{"bucketName": 1}}
+		_, err = settings.EffectiveStorage()
+		require.ErrorContains(t, err, "storage bucket: decoding MINIO bucket config")
+	})
+
+	t.Run("bucket validation", func(t *testing.T) {
+		for _, tc := range []struct {
+			name    string
+			bucket  modelv2.Bucket
+			missing []string
+		}{
+			{
+				name:   "s3 with environment credentials",
+				bucket: modelv2.Bucket{Type: modelv2.BucketTypeS3, Config: map[string]any{"bucketName": "b"}},
+			},
+			{
+				name:   "s3 with access key",
+				bucket: modelv2.Bucket{Type: modelv2.BucketTypeS3, Config: map[string]any{"bucketName": "b", "accessKeyID": "id", "accessKey": "key"}},
+			},
+			{
+				name:    "s3 with partial access key",
+				bucket:  modelv2.Bucket{Type: modelv2.BucketTypeS3, Config: map[string]any{"accessKeyID": "id"}},
+				missing: []string{"bucketName", "accessKey"},
+			},
+			{
+				name:    "s3 with role",
+				bucket:  modelv2.Bucket{Type: modelv2.BucketTypeS3, Config: map[string]any{"bucketName": "b", "roleBasedAuth": true}},
+				missing: []string{"iamRoleARN"},
+			},
+			{
+				name:    "gcs",
+				bucket:  modelv2.Bucket{Type: modelv2.BucketTypeGCS, Config: map[string]any{"bucketName": "b"}},
+				missing: []string{"credentials"},
+			},
+			{
+				name:   "azure with sas token",
+				bucket: modelv2.Bucket{Type: modelv2.BucketTypeAzureBlob, Config: map[string]any{"containerName": "c", "accountName": "a", "useSASTokens": true, "sasToken": "token"}},
+			},
+			{
+				name:    "azure without sas token",
+				bucket:  modelv2.Bucket{Type: modelv2.BucketTypeAzureBlob, Config: map[string]any{"containerName": "c", "accountName": "a", "useSASTokens": true}},
+				missing: []string{"sasToken"},
+			},
+			{
+				name:    "minio",
+				bucket:  modelv2.Bucket{Type: modelv2.BucketTypeMinIO, Config: map[string]any{"bucketName": "b", "endPoint": "localhost:9000"}},
+				missing: []string{"accessKeyID", "secretAccessKey"},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := tc.bucket.ValidConfig()
+				if tc.missing == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.Equal(t, &modelv2.BucketConfigError{Type: tc.bucket.Type, MissingFields: tc.missing}, err)
+			})
+		}
+	})
+
+	t.Run("redaction", func(t *testing.T) {
+		wcs := &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"workspace-1": {Settings: &modelv2.Settings{
+			DataRetention: modelv2.DataRetention{StorageBucket: modelv2.Bucket{
+				Type:   modelv2.BucketTypeMinIO,
+				Config: map[string]any{"bucketName": "b", "secretAccessKey": "secret"},
+			}},
+		}}}}
+		bucket := wcs.Redacted().Workspaces["workspace-1"].Settings.DataRetention.StorageBucket
+		require.Equal(t, "b", bucket.Config["bucketName"])
+		require.Equal(t, modelv2.RedactedValue, bucket.Config["secretAccessKey"])
+		require.Equal(t, "secret", wcs.Workspaces["workspace-1"].Settings.DataRetention.StorageBucket.Config["secretAccessKey"])
+	})
+}
@@ -1,21 +1,41 @@
 package modelv2
 
+import "time"
+
 type Source struct {
-	Name               string                `json:"name"`
-	WriteKey           string                `json:"writeKey"`
-	Enabled            bool                  `json:"enabled"`
-	Deleted            bool                  `json:"deleted"`
-	Config             map[string]any        `json:"config"`
-	Transient          bool                  `json:"transient"`
-	DefinitionName     string                `json:"sourceDefinitionName"`
-	SecretVersion      int                   `json:"secretVersion"`
-	AccountID          string                `json:"accountId"`
-	TrackingPlanConfig *DGSourceTrackingPlan `json:"dgSourceTrackingPlanConfig"`
-	// TODO: consider adding the following fields
-	// CreatedBy string `json:"createdBy"`
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
-	// LiveEventsConfig ...
+	Name               string                  `json:"name"`
+	WriteKey           string                  `json:"writeKey"`
+	Enabled            bool                    `json:"enabled"`
+	Deleted            bool                    `json:"deleted"`
+	Config             map[string]any          `json:"config"`
+	Transient          bool                    `json:"transient"`
+	DefinitionName     string                  `json:"sourceDefinitionName"`
+	SecretVersion      int                     `json:"secretVersion"`
+	AccountID          string                  `json:"accountId"`
+	TrackingPlanConfig *DGSourceTrackingPlan   `json:"dgSourceTrackingPlanConfig"`
+	CreatedBy          string                  `json:"createdBy"`
+	CreatedAt          time.Time               `json:"createdAt"`
+	UpdatedAt          time.Time               `json:"updatedAt"`
+	LiveEventsConfig   *SourceLiveEventsConfig `json:"liveEventsConfig"`
+	GeoEnrichment      *GeoEnrichment          `json:"geoEnrichment"`
+	Extra              Extra                   `json:"-"`
+}
+
+func (s *Source) GetUpdatedAt() time.Time { return s.UpdatedAt }
+
+// SourceLiveEventsConfig is the live events configuration of a source, i.e. whether the events it receives are
+// uploaded to be shown as live events.
+type SourceLiveEventsConfig struct {
+	EventUpload bool `json:"eventUpload"`
+	// EventUploadTS is the time (in milliseconds since the Unix epoch) when EventUpload was last changed.
+	EventUploadTS int64 `json:"eventUploadTS"`
+	Extra         Extra `json:"-"`
+}
+
+// GeoEnrichment is the configuration of the geolocation enrichment of the events of a source.
+type GeoEnrichment struct {
+	Enabled bool  `json:"enabled"`
+	Extra   Extra `json:"-"`
 }
 
 type SourceDefinition struct {
@@ -24,7 +44,9 @@ type SourceDefinition struct {
 	Category    string         `json:"category"`
 	Options     map[string]any `json:"options"`
 	Config      map[string]any `json:"config"`
-	// TODO: consider adding the following fields
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	Extra       Extra          `json:"-"`
 }
+
+func (sd *SourceDefinition) GetUpdatedAt() time.Time { return sd.UpdatedAt }
@@ -3,9 +3,11 @@ package modelv2
 type SQLModelVersion struct {
 	SQLModelID string `json:"sqlModelId"`
 	AccountID  string `json:"accountId"`
+	Extra      Extra  `json:"-"`
 }
 
 type SQLModelVersionSourceConnection struct {
 	SourceID          string `json:"sourceId"`
 	SQLModelVersionID string `json:"sqlModelVersionId"`
+	Extra             Extra  `json:"-"`
 }
@@ -1,14 +1,83 @@
 package modelv2
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
 type TrackingPlan struct {
-	Version int `json:"version"`
+	Version int   `json:"version"`
+	Extra   Extra `json:"-"`
 }
 
+// EventType is the type of an event, e.g. "track" or "identify".
+type EventType string
+
+const (
+	EventTypeTrack    EventType = "track"
+	EventTypeIdentify EventType = "identify"
+	EventTypeGroup    EventType = "group"
+	EventTypePage     EventType = "page"
+	EventTypeScreen   EventType = "screen"
+	EventTypeAlias    EventType = "alias"
+)
+
 type DGSourceTrackingPlanConfig struct {
 	Global   *DGSourceTrackingPlanOptions `json:"global"`
 	Track    *DGSourceTrackingPlanOptions `json:"track"`
 	Identify *DGSourceTrackingPlanOptions `json:"identify"`
 	Group    *DGSourceTrackingPlanOptions `json:"group"`
+	Extra    Extra                        `json:"-"`
+}
+
+// Options returns the effective options for events of the given type, i.e. the global options overridden by the
+// ones set for the event type, if any. AJV options are merged key by key. The returned options can be modified
+// freely.
+func (c *DGSourceTrackingPlanConfig) Options(eventType EventType) *DGSourceTrackingPlanOptions {
+	options := c.Global.DeepCopy()
+	if options == nil {
+		options = &DGSourceTrackingPlanOptions{}
+	}
+	var override *DGSourceTrackingPlanOptions
+	switch eventType {
+	case EventTypeTrack:
+		override = c.Track
+	case EventTypeIdentify:
+		override = c.Identify
+	case EventTypeGroup:
+		override = c.Group
+	}
+	if override == nil {
+		return options
+	}
+	override = override.DeepCopy()
+	if override.AllowUnplannedEvents.IsSet() {
+		options.AllowUnplannedEvents = override.AllowUnplannedEvents
+	}
+	if override.SendViolatedEventsTo != "" {
+		options.SendViolatedEventsTo = override.SendViolatedEventsTo
+	}
+	if override.AJVOptions != nil {
+		if options.AJVOptions == nil {
+			options.AJVOptions = make(map[string]any, len(override.AJVOptions))
+		}
+		maps.Copy(options.AJVOptions, override.AJVOptions)
+	}
+	if override.Extra != nil {
+		if options.Extra == nil {
+			options.Extra = make(Extra, len(override.Extra))
+		}
+		maps.Copy(options.Extra, override.Extra)
+	}
+	return options
 }
 
 type DGSourceTrackingPlan struct {
@@ -16,13 +85,172 @@ type DGSourceTrackingPlan struct {
 	Version        int                        `json:"version"`
 	Config         DGSourceTrackingPlanConfig `json:"config"`
 	Deleted        bool                       `json:"deleted"`
-	// TODO: consider adding the following fields
-	// CreatedAt time.Time `json:"createdAt"`
-	// UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt      time.Time                  `json:"createdAt"`
+	UpdatedAt      time.Time                  `json:"updatedAt"`
+	Extra          Extra                      `json:"-"`
 }
 
+func (tp *DGSourceTrackingPlan) GetUpdatedAt() time.Time { return tp.UpdatedAt }
+
 type DGSourceTrackingPlanOptions struct {
-	AllowUnplannedEvents any            `json:"allowUnplannedEvents"`
+	AllowUnplannedEvents OptionalBool   `json:"allowUnplannedEvents"`
 	SendViolatedEventsTo string         `json:"sendViolatedEventsTo"`
 	AJVOptions           map[string]any `json:"ajvOptions"`
+	Extra                Extra          `json:"-"`
+}
+
+// OptionalBool is a boolean that can be unset. It holds the JSON value it was decoded from, so that it is encoded
+// back as it was received: true, 1 and their string forms are true, false, 0 and their string forms are false, and
+// any other value (e.g. null or an empty string) means unset. Use [OptionalBool.IsSet] and [OptionalBool.Bool] rather
+// than comparing it with the constants below, which only match the JSON booleans.
+type OptionalBool string
+
+const (
+	OptionalBoolUnset OptionalBool = ""
+	OptionalBoolFalse OptionalBool = "false"
+	OptionalBoolTrue  OptionalBool = "true"
+)
+
+// IsSet tells whether the value is set.
+func (b OptionalBool) IsSet() bool {
+	_, ok := b.parse()
+	return ok
+}
+
+// Bool returns the value, false if unset.
+func (b OptionalBool) Bool() bool {
+	value, _ := b.parse()
+	return value
+}
+
+// parse returns the boolean held by the JSON value, and whether it holds one.
+func (b OptionalBool) parse() (value, ok bool) {
+	switch b {
+	case OptionalBoolTrue:
+		return true, true
+	case OptionalBoolFalse:
+		return false, true
+	}
+	if strings.HasPrefix(string(b), `"`) {
+		var s string
+		if err := jsonrs.Unmarshal([]byte(b), &s); err != nil {
+			return false, false
+		}
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true", "1":
+			return true, true
+		case "false", "0":
+			return false, true
+		}
+		return false, false
+	}
+	if n, err := strconv.ParseFloat(string(b), 64); err == nil {
+		switch n {
+		case 0:
+			return false, true
+		case 1:
+			return true, true
+		}
+	}
+	return false, false
+}
+
+func (b OptionalBool) MarshalJSON() ([]byte, error) {
+	if b == OptionalBoolUnset {
+		return []byte("null"), nil
+	}
+	if !json.Valid([]byte(b)) {
+		return nil, fmt.Errorf("optional bool %q is not valid JSON", string(b))
+	}
+	return []byte(b), nil
+}
+
+// UnmarshalJSON never fails on well-formed JSON, values it doesn't recognize being kept as they are rather than
+// failing the decoding of the whole workspace config.
+func (b *OptionalBool) UnmarshalJSON(data []byte) error {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return err
+	}
+	*b = OptionalBool(compacted.String())
+	if *b == "null" {
+		*b = OptionalBoolUnset
+	}
+	return nil
+}
+
+// ErrNoTrackingPlan is returned when resolving the tracking plan of a source that isn't connected to any.
+var ErrNoTrackingPlan = errors.New("no tracking plan connected")
+
+// MissingTrackingPlanError is returned when the tracking plan a source is connected to is not part of the workspace.
+type MissingTrackingPlanError struct {
+	SourceID       string
+	TrackingPlanID string
+}
+
+func (e *MissingTrackingPlanError) Error() string {
+	return fmt.Sprintf("tracking plan %q of source %q not found", e.TrackingPlanID, e.SourceID)
+}
+
+// TrackingPlanVersionError is returned when a source is connected to a version of a tracking plan that doesn't exist,
+// i.e. it isn't between 1 and the latest version of the plan.
+type TrackingPlanVersionError struct {
+	SourceID       string
+	TrackingPlanID string
+	Version        int
+	LatestVersion  int
+}
+
+func (e *TrackingPlanVersionError) Error() string {
+	return fmt.Sprintf("tracking plan %q of source %q has no version %d, latest version is %d",
+		e.TrackingPlanID, e.SourceID, e.Version, e.LatestVersion)
+}
+
+// DeletedTrackingPlanError is returned when the tracking plan connection of a source is deleted.
+type DeletedTrackingPlanError struct {
+	SourceID       string
+	TrackingPlanID string
+}
+
+func (e *DeletedTrackingPlanError) Error() string {
+	return fmt.Sprintf("tracking plan %q of source %q is deleted", e.TrackingPlanID, e.SourceID)
+}
+
+// SourceTrackingPlan returns the tracking plan connection of a source, checking that it is not deleted and that the
+// connected tracking plan version exists in the workspace.
+func (wc *WorkspaceConfig) SourceTrackingPlan(sourceID string) (*DGSourceTrackingPlan, error) {
+	source := wc.Sources[sourceID]
+	if source == nil {
+		return nil, fmt.Errorf("source %q not found", sourceID)
+	}
+	tp := source.TrackingPlanConfig
+	if tp == nil || tp.TrackingPlanID == "" {
+		return nil, fmt.Errorf("source %q: %w", sourceID, ErrNoTrackingPlan)
+	}
+	if tp.Deleted {
+		return nil, &DeletedTrackingPlanError{SourceID: sourceID, TrackingPlanID: tp.TrackingPlanID}
+	}
+	plan := wc.TrackingPlans[tp.TrackingPlanID]
+	if plan == nil {
+		return nil, &MissingTrackingPlanError{SourceID: sourceID, TrackingPlanID: tp.TrackingPlanID}
+	}
+	if tp.Version < 1 || tp.Version > plan.Version {
+		return nil, &TrackingPlanVersionError{
+			SourceID:       sourceID,
+			TrackingPlanID: tp.TrackingPlanID,
+			Version:        tp.Version,
+			LatestVersion:  plan.Version,
+		}
+	}
+	return tp, nil
+}
+
+// SourceTrackingPlanOptions returns the effective tracking plan options of a source for events of the given type,
+// after resolving its tracking plan with [WorkspaceConfig.SourceTrackingPlan].
+func (wc *WorkspaceConfig) SourceTrackingPlanOptions(sourceID string, eventType EventType) (*DGSourceTrackingPlanOptions, error) {
+	tp, err := wc.SourceTrackingPlan(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	return tp.Config.Options(eventType), nil
 }
@@ -0,0 +1,151 @@
+package modelv2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestTrackingPlans(t *testing.T) {
+	t.Run("allow unplanned events", func(t *testing.T) {
+		for _, tc := range []struct {
+			json     string
+			set      bool
+			expected bool
+		}{
+			{`{}`, false, false},
+			{`{"allowUnplannedEvents": null}`, false, false},
+			{`{"allowUnplannedEvents": ""}`, false, false},
+			{`{"allowUnplannedEvents": true}`, true, true},
+			{`{"allowUnplannedEvents": false}`, true, false},
+			{`{"allowUnplannedEvents": "true"}`, true, true},
+			{`{"allowUnplannedEvents": " False "}`, true, false},
+			{`{"allowUnplannedEvents": 0}`, true, false},
+			{`{"allowUnplannedEvents": 1}`, true, true},
+			{`{"allowUnplannedEvents": 1.0}`, true, true},
+			{`{"allowUnplannedEvents": "1"}`, true, true},
+			{`{"allowUnplannedEvents": "yes"}`, false, false},
+			{`{"allowUnplannedEvents": 2}`, false, false},
+			{`{"allowUnplannedEvents": { "a": [ 1 ] }}`, false, false},
+		} {
+			var options modelv2.DGSourceTrackingPlanOptions
+			require.NoError(t, jsonrs.Unmarshal([]byte(tc.json), &options), tc.json)
+			require.Equal(t, tc.set, options.AllowUnplannedEvents.IsSet(), tc.json)
+			require.Equal(t, tc.expected, options.AllowUnplannedEvents.Bool(), tc.json)
+
+			// values are encoded back as they were received, whether they are recognized or not
+			encoded, err := jsonrs.Marshal(options)
+			require.NoError(t, err)
+			var expected, actual map[string]any
+			require.NoError(t, jsonrs.Unmarshal([]byte(tc.json), &expected))
+			require.NoError(t, jsonrs.Unmarshal(encoded, &actual))
+			if value, ok := expected["allowUnplannedEvents"]; ok {
+				require.Equal(t, value, actual["allowUnplannedEvents"], tc.json)
+			} else {
+				require.Nil(t, actual["allowUnplannedEvents"], tc.json)
+			}
+		}
+
+		var options modelv2.DGSourceTrackingPlanOptions
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{"allowUnplannedEvents": true}`), &options))
+		require.Equal(t, modelv2.OptionalBoolTrue, options.AllowUnplannedEvents)
+
+		_, err := jsonrs.Marshal(modelv2.DGSourceTrackingPlanOptions{AllowUnplannedEvents: "yes"})
+		require.ErrorContains(t, err, `optional bool "yes" is not valid JSON`)
+
+		encoded, err := jsonrs.Marshal(modelv2.DGSourceTrackingPlanOptions{AllowUnplannedEvents: modelv2.OptionalBoolFalse})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"allowUnplannedEvents": false, "sendViolatedEventsTo": "", "ajvOptions": null}`, string(encoded))
+		encoded, err = jsonrs.Marshal(modelv2.DGSourceTrackingPlanOptions{})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"allowUnplannedEvents": null, "sendViolatedEventsTo": "", "ajvOptions": null}`, string(encoded))
+	})
+
+	t.Run("effective options", func(t *testing.T) {
+		config := modelv2.DGSourceTrackingPlanConfig{
+			Global: &modelv2.DGSourceTrackingPlanOptions{
+				AllowUnplannedEvents: modelv2.OptionalBoolTrue,
+				SendViolatedEventsTo: "procerrors",
+				AJVOptions:           map[string]any{"strict": true, "allErrors": false},
+			},
+			Track: &modelv2.DGSourceTrackingPlanOptions{
+				AllowUnplannedEvents: modelv2.OptionalBoolFalse,
+				AJVOptions:           map[string]any{"allErrors": true},
+			},
+			Identify: &modelv2.DGSourceTrackingPlanOptions{SendViolatedEventsTo: "destinations"},
+		}
+
+		require.Equal(t, &modelv2.DGSourceTrackingPlanOptions{
+			AllowUnplannedEvents: modelv2.OptionalBoolFalse,
+			SendViolatedEventsTo: "procerrors",
+			AJVOptions:           map[string]any{"strict": true, "allErrors": true},
+		}, config.Options(modelv2.EventTypeTrack))
+		require.Equal(t, &modelv2.DGSourceTrackingPlanOptions{
+			AllowUnplannedEvents: modelv2.OptionalBoolTrue,
+			SendViolatedEventsTo: "destinations",
+			AJVOptions:           map[string]any{"strict": true, "allErrors": false},
+		}, config.Options(modelv2.EventTypeIdentify))
+		require.Equal(t, config.Global, config.Options(modelv2.EventTypePage))
+		require.Equal(t, config.Global, config.Options(modelv2.EventTypeGroup))
+
+		config.Options(modelv2.EventTypeTrack).AJVOptions["strict"] = false
+		require.Equal(t, true, config.Global.AJVOptions["strict"], "the config should not be modified")
+
+		require.Equal(t, &modelv2.DGSourceTrackingPlanOptions{}, (&modelv2.DGSourceTrackingPlanConfig{}).Options(modelv2.EventTypeTrack))
+	})
+
+	t.Run("source tracking plan", func(t *testing.T) {
+		connection := func(id string, version int, deleted bool) *modelv2.DGSourceTrackingPlan {
+			return &modelv2.DGSourceTrackingPlan{
+				TrackingPlanID: id,
+				Version:        version,
+				Deleted:        deleted,
+				Config: modelv2.DGSourceTrackingPlanConfig{
+					Global: &modelv2.DGSourceTrackingPlanOptions{AllowUnplannedEvents: modelv2.OptionalBoolTrue},
+				},
+			}
+		}
+		wc := &modelv2.WorkspaceConfig{
+			TrackingPlans: map[string]*modelv2.TrackingPlan{"tp-1": {Version: 2}},
+			Sources: map[string]*modelv2.Source{
+				"source-1": {TrackingPlanConfig: connection("tp-1", 2, false)},
+				"source-2": {TrackingPlanConfig: connection("tp-1", 1, false)},
+				"source-3": {TrackingPlanConfig: connection("tp-1", 3, false)},
+				"source-4": {TrackingPlanConfig: connection("tp-2", 1, false)},
+				"source-5": {TrackingPlanConfig: connection("tp-1", 2, true)},
+				"source-6": {},
+			},
+		}
+
+		for _, sourceID := range []string{"source-1", "source-2"} {
+			options, err := wc.SourceTrackingPlanOptions(sourceID, modelv2.EventTypeTrack)
+			require.NoError(t, err)
+			require.True(t, options.AllowUnplannedEvents.Bool())
+		}
+
+		_, err := wc.SourceTrackingPlanOptions("source-3", modelv2.EventTypeTrack)
+		var versionErr *modelv2.TrackingPlanVersionError
+		require.ErrorAs(t, err, &versionErr)
+		require.EqualError(t, err, `tracking plan "tp-1" of source "source-3" has no version 3, latest version is 2`)
+
+		_, err = wc.SourceTrackingPlanOptions("source-4", modelv2.EventTypeTrack)
+		var missingErr *modelv2.MissingTrackingPlanError
+		require.ErrorAs(t, err, &missingErr)
+		require.Equal(t, &modelv2.MissingTrackingPlanError{SourceID: "source-4", TrackingPlanID: "tp-2"}, missingErr)
+
+		_, err = wc.SourceTrackingPlanOptions("source-5", modelv2.EventTypeTrack)
+		var deletedErr *modelv2.DeletedTrackingPlanError
+		require.ErrorAs(t, err, &deletedErr)
+		require.EqualError(t, err, `tracking plan "tp-1" of source "source-5" is deleted`)
+
+		_, err = wc.SourceTrackingPlanOptions("source-6", modelv2.EventTypeTrack)
+		require.ErrorIs(t, err, modelv2.ErrNoTrackingPlan)
+
+		_, err = wc.SourceTrackingPlanOptions("source-7", modelv2.EventTypeTrack)
+		require.EqualError(t, err, `source "source-7" not found`)
+	})
+}
@@ -2,10 +2,20 @@ package modelv2
 
 type Library struct {
 	VersionID string `json:"versionId"`
+	Extra     Extra  `json:"-"`
 }
 
 type Transformation struct {
-	VersionID string `json:"versionId"`
-	// TODO: consider adding the following fields
-	// LiveEventsConfig ...
+	VersionID        string                          `json:"versionId"`
+	LiveEventsConfig *TransformationLiveEventsConfig `json:"liveEventsConfig"`
+	Extra            Extra                           `json:"-"`
+}
+
+// TransformationLiveEventsConfig is the live events configuration of a transformation, i.e. whether the events it
+// transforms are uploaded to be shown as live events.
+type TransformationLiveEventsConfig struct {
+	EventTransform bool `json:"eventTransform"`
+	// EventTransformTS is the time (in milliseconds since the Unix epoch) when EventTransform was last changed.
+	EventTransformTS int64 `json:"eventTransformTS"`
+	Extra            Extra `json:"-"`
 }
@@ -1,18 +1,286 @@
 package modelv2
 
-type WHTProjectConfig struct { // TODO: these should not be arbitrary maps
-	Resources       []string       `json:"resources"`
-	Schedule        map[string]any `json:"schedule"`
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// WHTScheduleType is the type of the schedule of a WHT project.
+type WHTScheduleType string
+
+const (
+	// WHTScheduleTypeCron schedules runs with a cron expression.
+	WHTScheduleTypeCron WHTScheduleType = "cron"
+	// WHTScheduleTypeInterval schedules runs at a fixed interval.
+	WHTScheduleTypeInterval WHTScheduleType = "interval"
+	// WHTScheduleTypeManual doesn't schedule runs, projects only run when triggered.
+	WHTScheduleTypeManual WHTScheduleType = "manual"
+)
+
+// WHTSchedule is the typed schedule of a WHT project, telling when it runs, see [WHTProjectConfig.TypedSchedule].
+// Only the fields of its type are relevant.
+//
+// It is provisional: its fields are not taken from actual control plane responses, none of which carry a schedule
+// yet, thus they may be renamed or replaced once the format of schedules is settled. [WHTProjectConfig.Schedule]
+// keeps the schedule as it is received in the meantime.
+type WHTSchedule struct {
+	Type WHTScheduleType `json:"type"`
+	// CronExpression is the cron expression of cron schedules, e.g. "0 */6 * * *", see [WHTSchedule.Validate] for
+	// the supported syntax.
+	CronExpression string `json:"cronExpression"`
+	// Timezone is the IANA name of the timezone cron expressions are evaluated in, UTC if empty.
+	Timezone string `json:"timezone"`
+	// IntervalSeconds is the time between the runs of interval schedules.
+	IntervalSeconds int64 `json:"intervalSeconds"`
+	// StartTime is when interval schedules start, if set. Runs are then aligned to it.
+	StartTime time.Time `json:"startTime"`
+}
+
+// Validate checks that the schedule is of a known type and that the fields of its type are valid.
+//
+// Cron expressions follow the five fields (minute, hour, day of month, month and day of week) dialect of Vixie cron,
+// i.e. the crontab(5) one of most Linux distributions: fields are lists of values, ranges (1-5) and steps (*/15 or
+// 1-30/2), months and days of week can be given by their case insensitive 3 letter English names, 0 and 7 are both
+// Sunday, and when neither day field starts with "*" days matching either of them match. The @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly macros are supported too, while seconds, years and the L, W, #
+// and ? specifiers of other dialects (e.g. Quartz) are not.
+func (s *WHTSchedule) Validate() error {
+	switch s.Type {
+	case WHTScheduleTypeCron:
+		_, _, err := s.cron()
+		return err
+	case WHTScheduleTypeInterval:
+		if s.IntervalSeconds <= 0 {
+			return fmt.Errorf("interval schedule: interval should be positive, got %d seconds", s.IntervalSeconds)
+		}
+		return nil
+	case WHTScheduleTypeManual:
+		return nil
+	default:
+		return fmt.Errorf("unknown schedule type %q", s.Type)
+	}
+}
+
+// Next returns the time of the next run according to the schedule, given the current time and the time of the last
+// run, which is zero if there was none. Runs that should have happened before now are due now. It returns false for
+// manual schedules and cron expressions that never match.
+func (s *WHTSchedule) Next(now, lastRun time.Time) (time.Time, bool, error) {
+	if err := s.Validate(); err != nil {
+		return time.Time{}, false, err
+	}
+	var next time.Time
+	switch s.Type {
+	case WHTScheduleTypeCron:
+		cron, loc, _ := s.cron()
+		from := lastRun
+		if from.IsZero() {
+			from = now
+		}
+		if next = cron.next(from.In(loc)); next.IsZero() {
+			return time.Time{}, false, nil
+		}
+	case WHTScheduleTypeInterval:
+		interval := time.Duration(s.IntervalSeconds) * time.Second
+		switch {
+		case !s.StartTime.IsZero() && lastRun.Before(s.StartTime):
+			next = s.StartTime
+		case !s.StartTime.IsZero():
+			next = s.StartTime.Add((lastRun.Sub(s.StartTime)/interval + 1) * interval)
+		case lastRun.IsZero():
+			next = now
+		default:
+			next = lastRun.Add(interval)
+		}
+	default:
+		return time.Time{}, false, nil
+	}
+	if next.Before(now) {
+		next = now
+	}
+	return next, true, nil
+}
+
+func (s *WHTSchedule) cron() (*cronSchedule, *time.Location, error) {
+	cron, err := parseCron(s.CronExpression)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cron schedule: %w", err)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cron schedule: invalid timezone %q: %w", s.Timezone, err)
+	}
+	return cron, loc, nil
+}
+
+// WHTRetentionPolicy is the typed retention policy of a WHT project, telling for how long the outputs of its runs are
+// kept, see [WHTProjectConfig.TypedRetentionPolicy].
+//
+// It is provisional, like [WHTSchedule]: [WHTProjectConfig.RetentionPolicy] keeps the retention policy as it is
+// received.
+type WHTRetentionPolicy struct {
+	// Days is the number of days the outputs of runs are kept, zero meaning forever.
+	Days int `json:"days"`
+	// MaxRuns is the number of most recent runs whose outputs are kept, zero meaning all of them.
+	MaxRuns int `json:"maxRuns"`
+}
+
+// Validate checks that the limits of the retention policy are not negative.
+func (p *WHTRetentionPolicy) Validate() error {
+	if p.Days < 0 {
+		return fmt.Errorf("retention policy: days should not be negative, got %d", p.Days)
+	}
+	if p.MaxRuns < 0 {
+		return fmt.Errorf("retention policy: max runs should not be negative, got %d", p.MaxRuns)
+	}
+	return nil
+}
+
+type WHTProjectConfig struct {
+	// Resources are the IDs of the workspace resources the project uses, see [WorkspaceConfig.WHTProjectResources].
+	Resources []string `json:"resources"`
+	// Schedule is kept as it is received, see [WHTProjectConfig.TypedSchedule].
+	Schedule map[string]any `json:"schedule"`
+	// RetentionPolicy is kept as it is received, see [WHTProjectConfig.TypedRetentionPolicy].
 	RetentionPolicy map[string]any `json:"retentionPolicy"`
+	Extra           Extra          `json:"-"`
+}
+
+// TypedSchedule decodes the schedule of the project, returning nil if it has none. It is not validated, see
+// [WHTSchedule.Validate]. Note that [WHTSchedule] is provisional.
+func (c *WHTProjectConfig) TypedSchedule() (*WHTSchedule, error) {
+	if c.Schedule == nil {
+		return nil, nil
+	}
+	schedule := &WHTSchedule{}
+	if err := decodeMap(c.Schedule, schedule); err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// TypedRetentionPolicy decodes the retention policy of the project, returning nil if it has none. It is not
+// validated, see [WHTRetentionPolicy.Validate]. Note that [WHTRetentionPolicy] is provisional.
+func (c *WHTProjectConfig) TypedRetentionPolicy() (*WHTRetentionPolicy, error) {
+	if c.RetentionPolicy == nil {
+		return nil, nil
+	}
+	policy := &WHTRetentionPolicy{}
+	if err := decodeMap(c.RetentionPolicy, policy); err != nil {
+		return nil, fmt.Errorf("retention policy: %w", err)
+	}
+	return policy, nil
+}
+
+// decodeMap decodes a JSON object kept as a map into v.
+func decodeMap(m map[string]any, v any) error {
+	data, err := jsonrs.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encoding: %w", err)
+	}
+	if err := jsonrs.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+	return nil
+}
+
+// Validate decodes and checks the schedule and retention policy of the project, if any.
+func (c *WHTProjectConfig) Validate() error {
+	schedule, err := c.TypedSchedule()
+	if err == nil && schedule != nil {
+		err = schedule.Validate()
+	}
+	policy, policyErr := c.TypedRetentionPolicy()
+	if policyErr == nil && policy != nil {
+		policyErr = policy.Validate()
+	}
+	return errors.Join(err, policyErr)
 }
 
 type WHTProject struct {
-	Name               string           `json:"name"`
-	Descrition         string           `json:"description"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Deprecated: use Description. It is set to the same value when decoding, and only encoded if Description is
+	// empty.
+	Descrition         string           `json:"-"`
 	Config             WHTProjectConfig `json:"config"`
 	SSHKeyID           string           `json:"sshKeyId"`
 	Enabled            bool             `json:"enabled"`
 	Deleted            bool             `json:"deleted"`
 	GitRepoAccountID   string           `json:"gitRepoAccountId"`
 	WarehouseAccountID string           `json:"warehouseAccountId"`
+	Extra              Extra            `json:"-"`
+}
+
+// UnmarshalJSON decodes the WHTProject, keeping the fields it doesn't model in Extra.
+func (in *WHTProject) UnmarshalJSON(data []byte) error {
+	type plain WHTProject
+	if err := unmarshalWithExtra(data, (*plain)(in), &in.Extra); err != nil {
+		return err
+	}
+	in.Descrition = in.Description
+	return nil
+}
+
+// MarshalJSON encodes the WHTProject, including the fields in Extra.
+func (in WHTProject) MarshalJSON() ([]byte, error) {
+	type plain WHTProject
+	if in.Description == "" {
+		in.Description = in.Descrition
+	}
+	return marshalWithExtra(plain(in), in.Extra)
+}
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// NextRun returns the time of the next scheduled run of the project, given the time of its last run, which is zero
+// if it never ran. It returns false if the project doesn't run on a schedule, i.e. it is disabled, deleted or has no
+// or a manual schedule. See [WHTSchedule.Next].
+func (p *WHTProject) NextRun(clock Clock, lastRun time.Time) (time.Time, bool, error) {
+	if !p.Enabled || p.Deleted {
+		return time.Time{}, false, nil
+	}
+	schedule, err := p.Config.TypedSchedule()
+	if err != nil || schedule == nil {
+		return time.Time{}, false, err
+	}
+	return schedule.Next(clock.Now(), lastRun)
+}
+
+// MissingResourcesError is returned when resources used by a WHT project are not part of the workspace.
+type MissingResourcesError struct {
+	ProjectID   string
+	ResourceIDs []string
+}
+
+func (e *MissingResourcesError) Error() string {
+	return fmt.Sprintf("resources %q used by WHT project %q not found", e.ResourceIDs, e.ProjectID)
+}
+
+// WHTProjectResources returns the resources used by a WHT project, in the order of its configuration. If any of them
+// is not part of the workspace a [*MissingResourcesError] is returned.
+func (wc *WorkspaceConfig) WHTProjectResources(projectID string) ([]*Resource, error) {
+	project := wc.WHTProjects[projectID]
+	if project == nil {
+		return nil, fmt.Errorf("WHT project %q not found", projectID)
+	}
+	resources := make([]*Resource, 0, len(project.Config.Resources))
+	var missing []string
+	for _, id := range project.Config.Resources {
+		resource := wc.Resources[id]
+		if resource == nil {
+			missing = append(missing, id)
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingResourcesError{ProjectID: projectID, ResourceIDs: missing}
+	}
+	return resources, nil
 }
@@ -0,0 +1,323 @@
+package modelv2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestWHTProjects(t *testing.T) {
+	t.Run("decoding", func(t *testing.T) {
+		var project modelv2.WHTProject
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{
+			"name": "profiles",
+			"description": "customer 360",
+			"config": {
+				"resources": ["resource-1"],
+				"schedule": {"type": "cron", "cronExpression": "0 */6 * * *", "timezone": "Europe/Athens"},
+				"retentionPolicy": {"days": 30, "maxRuns": 10}
+			},
+			"enabled": true
+		}`), &project))
+		require.Equal(t, modelv2.WHTProject{
+			Name:        "profiles",
+			Description: "customer 360",
+			Descrition:  "customer 360",
+			Config: modelv2.WHTProjectConfig{
+				Resources:       []string{"resource-1"},
+				Schedule:        map[string]any{"type": "cron", "cronExpression": "0 */6 * * *", "timezone": "Europe/Athens"},
+				RetentionPolicy: map[string]any{"days": 30.0, "maxRuns": 10.0},
+			},
+			Enabled: true,
+		}, project)
+		require.NoError(t, project.Config.Validate())
+
+		schedule, err := project.Config.TypedSchedule()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.WHTSchedule{Type: modelv2.WHTScheduleTypeCron, CronExpression: "0 */6 * * *", Timezone: "Europe/Athens"}, schedule)
+		policy, err := project.Config.TypedRetentionPolicy()
+		require.NoError(t, err)
+		require.Equal(t, &modelv2.WHTRetentionPolicy{Days: 30, MaxRuns: 10}, policy)
+	})
+
+	t.Run("unexpected shapes", func(t *testing.T) {
+		// the project is decoded regardless of the shape of its schedule and retention policy
+		var project modelv2.WHTProject
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{
+			"config": {
+				"schedule": {"type": "cron", "cronExpression": ["0", "*", "*", "*", "*"], "owner": "team"},
+				"retentionPolicy": {"days": "thirty"}
+			}
+		}`), &project))
+		require.Equal(t, "team", project.Config.Schedule["owner"])
+
+		_, err := project.Config.TypedSchedule()
+		require.ErrorContains(t, err, "schedule: decoding")
+		_, err = project.Config.TypedRetentionPolicy()
+		require.ErrorContains(t, err, "retention policy: decoding")
+		require.ErrorContains(t, project.Config.Validate(), "retention policy: decoding")
+
+		schedule, err := (&modelv2.WHTProjectConfig{}).TypedSchedule()
+		require.NoError(t, err)
+		require.Nil(t, schedule)
+	})
+
+	t.Run("deprecated description", func(t *testing.T) {
+		data, err := jsonrs.Marshal(modelv2.WHTProject{Descrition: "old"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"","description":"old","config":{"resources":null,"schedule":null,"retentionPolicy":null},"sshKeyId":"","enabled":false,"deleted":false,"gitRepoAccountId":"","warehouseAccountId":""}`, string(data))
+
+		data, err = jsonrs.Marshal(modelv2.WHTProject{Description: "new", Descrition: "old"})
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"description":"new"`)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			config modelv2.WHTProjectConfig
+			err    string
+		}{
+			{name: "empty"},
+			{name: "manual", config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "manual"}}},
+			{name: "cron macro", config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "@daily"}}},
+			{name: "cron names", config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "0,30 9-17/2 * JAN-jun mon-FRI"}}},
+			{
+				name:   "unknown type",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "hourly"}},
+				err:    `unknown schedule type "hourly"`,
+			},
+			{
+				name:   "cron fields",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "0 * * *"}},
+				err:    `cron schedule: cron expression "0 * * *": expected 5 fields, got 4`,
+			},
+			{
+				name:   "cron value",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "0 24 * * *"}},
+				err:    `hour: invalid value "24", expected 0-23`,
+			},
+			{
+				name:   "cron range",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "0 0 10-5 * *"}},
+				err:    `day of month: invalid range "10-5"`,
+			},
+			{
+				name:   "cron step",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "*/0 * * * *"}},
+				err:    `minute: invalid step "0"`,
+			},
+			{
+				name:   "timezone",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron", "cronExpression": "@hourly", "timezone": "Mars/Olympus"}},
+				err:    `cron schedule: invalid timezone "Mars/Olympus"`,
+			},
+			{
+				name:   "interval",
+				config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "interval"}},
+				err:    "interval schedule: interval should be positive, got 0 seconds",
+			},
+			{
+				name: "retention policy",
+				config: modelv2.WHTProjectConfig{
+					Schedule:        map[string]any{"type": "interval"},
+					RetentionPolicy: map[string]any{"maxRuns": -1.0},
+				},
+				err: "retention policy: max runs should not be negative, got -1",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.config.Validate()
+				if tc.err == "" {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("next run", func(t *testing.T) {
+		athens, err := time.LoadLocation("Europe/Athens")
+		require.NoError(t, err)
+		now := time.Date(2024, 3, 15, 10, 20, 30, 0, time.UTC) // Friday
+		start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		for _, tc := range []struct {
+			name      string
+			schedule  map[string]any
+			lastRun   time.Time
+			next      time.Time
+			scheduled bool
+		}{
+			{name: "no schedule"},
+			{name: "manual", schedule: map[string]any{"type": "manual"}},
+			{
+				name:      "cron never ran",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 */6 * * *"},
+				next:      time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "cron after last run",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 */6 * * *"},
+				lastRun:   time.Date(2024, 3, 15, 6, 0, 0, 0, time.UTC),
+				next:      time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "cron missed run",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 */6 * * *"},
+				lastRun:   time.Date(2024, 3, 14, 18, 0, 0, 0, time.UTC),
+				next:      now,
+				scheduled: true,
+			},
+			{
+				name:      "cron in timezone",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "30 9 * * *", "timezone": "Europe/Athens"},
+				next:      time.Date(2024, 3, 16, 9, 30, 0, 0, athens),
+				scheduled: true,
+			},
+			{
+				name:      "cron day of week",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 8 * * MON"},
+				next:      time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "cron day of month or week",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 8 1 * 7"},
+				next:      time.Date(2024, 3, 17, 8, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "cron leap day",
+				schedule:  map[string]any{"type": "cron", "cronExpression": "0 0 29 2 *"},
+				next:      time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:     "cron never matches",
+				schedule: map[string]any{"type": "cron", "cronExpression": "0 0 30 2 *"},
+			},
+			{
+				name:      "interval never ran",
+				schedule:  map[string]any{"type": "interval", "intervalSeconds": 3600.0},
+				next:      now,
+				scheduled: true,
+			},
+			{
+				name:      "interval after last run",
+				schedule:  map[string]any{"type": "interval", "intervalSeconds": 3600.0},
+				lastRun:   time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
+				next:      time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "interval aligned to start time",
+				schedule:  map[string]any{"type": "interval", "intervalSeconds": 3600.0, "startTime": start.Format(time.RFC3339)},
+				lastRun:   time.Date(2024, 3, 15, 10, 5, 0, 0, time.UTC),
+				next:      time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC),
+				scheduled: true,
+			},
+			{
+				name:      "interval before start time",
+				schedule:  map[string]any{"type": "interval", "intervalSeconds": 3600.0, "startTime": now.Add(24 * time.Hour).Format(time.RFC3339)},
+				next:      now.Add(24 * time.Hour),
+				scheduled: true,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				project := &modelv2.WHTProject{Enabled: true, Config: modelv2.WHTProjectConfig{Schedule: tc.schedule}}
+				next, scheduled, err := project.NextRun(fixedClock(now), tc.lastRun)
+				require.NoError(t, err)
+				require.Equal(t, tc.scheduled, scheduled)
+				require.True(t, tc.next.Equal(next), "expected %s, got %s", tc.next, next)
+			})
+		}
+
+		t.Run("disabled or deleted", func(t *testing.T) {
+			schedule := map[string]any{"type": "interval", "intervalSeconds": 3600.0}
+			for _, project := range []*modelv2.WHTProject{
+				{Config: modelv2.WHTProjectConfig{Schedule: schedule}},
+				{Enabled: true, Deleted: true, Config: modelv2.WHTProjectConfig{Schedule: schedule}},
+			} {
+				_, scheduled, err := project.NextRun(fixedClock(now), time.Time{})
+				require.NoError(t, err)
+				require.False(t, scheduled)
+			}
+		})
+
+		// expressions from real crontabs, with the runs Vixie cron would start after now
+		t.Run("crontab expressions", func(t *testing.T) {
+			at := func(month time.Month, day, hour, minute int) time.Time {
+				return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+			}
+			for expr, runs := range map[string][]time.Time{
+				"*/15 9-17 * * MON-FRI": {at(3, 15, 10, 30), at(3, 15, 10, 45), at(3, 15, 11, 0)},
+				"0 0 1,15 * *":          {at(4, 1, 0, 0), at(4, 15, 0, 0), at(5, 1, 0, 0)},
+				"5 4 * * sun":           {at(3, 17, 4, 5), at(3, 24, 4, 5), at(3, 31, 4, 5)},
+				"0 0 1 */3 *":           {at(4, 1, 0, 0), at(7, 1, 0, 0), at(10, 1, 0, 0)},
+				"23 0-20/2 * * *":       {at(3, 15, 10, 23), at(3, 15, 12, 23), at(3, 15, 14, 23)},
+				"0 22 * * 1-5":          {at(3, 15, 22, 0), at(3, 18, 22, 0), at(3, 19, 22, 0)},
+				"30 2 * * 0,6":          {at(3, 16, 2, 30), at(3, 17, 2, 30), at(3, 23, 2, 30)},
+				// both day fields are restricted: the first 7 days of the month, or Mondays
+				"0 9 1-7 * 1": {at(3, 18, 9, 0), at(3, 25, 9, 0), at(4, 1, 9, 0)},
+				"@weekly":     {at(3, 17, 0, 0), at(3, 24, 0, 0), at(3, 31, 0, 0)},
+			} {
+				project := &modelv2.WHTProject{Enabled: true, Config: modelv2.WHTProjectConfig{
+					Schedule: map[string]any{"type": "cron", "cronExpression": expr},
+				}}
+				var lastRun time.Time
+				for _, run := range runs {
+					next, scheduled, err := project.NextRun(fixedClock(now), lastRun)
+					require.NoError(t, err, expr)
+					require.True(t, scheduled, expr)
+					require.True(t, run.Equal(next), "%s: expected %s, got %s", expr, run, next)
+					lastRun = next
+				}
+			}
+		})
+
+		t.Run("invalid schedule", func(t *testing.T) {
+			project := &modelv2.WHTProject{Enabled: true, Config: modelv2.WHTProjectConfig{Schedule: map[string]any{"type": "cron"}}}
+			_, _, err := project.NextRun(fixedClock(now), time.Time{})
+			require.ErrorContains(t, err, "cron schedule")
+		})
+	})
+
+	t.Run("resources", func(t *testing.T) {
+		wc := &modelv2.WorkspaceConfig{
+			Resources: map[string]*modelv2.Resource{
+				"resource-1": {Name: "resource 1"},
+				"resource-2": {Name: "resource 2"},
+			},
+			WHTProjects: map[string]*modelv2.WHTProject{
+				"project-1": {Config: modelv2.WHTProjectConfig{Resources: []string{"resource-2", "resource-1"}}},
+				"project-2": {Config: modelv2.WHTProjectConfig{Resources: []string{"resource-1", "resource-3", "resource-4"}}},
+			},
+		}
+
+		resources, err := wc.WHTProjectResources("project-1")
+		require.NoError(t, err)
+		require.Equal(t, []*modelv2.Resource{wc.Resources["resource-2"], wc.Resources["resource-1"]}, resources)
+
+		_, err = wc.WHTProjectResources("project-2")
+		var missingErr *modelv2.MissingResourcesError
+		require.ErrorAs(t, err, &missingErr)
+		require.Equal(t, &modelv2.MissingResourcesError{ProjectID: "project-2", ResourceIDs: []string{"resource-3", "resource-4"}}, missingErr)
+		require.EqualError(t, err, `resources ["resource-3" "resource-4"] used by WHT project "project-2" not found`)
+
+		_, err = wc.WHTProjectResources("project-3")
+		require.EqualError(t, err, `WHT project "project-3" not found`)
+	})
+}
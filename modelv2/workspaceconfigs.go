@@ -1,5 +1,7 @@
 package modelv2
 
+//go:generate go run ./internal/modelgen -type WorkspaceConfigs -type AccountSecret
+
 import (
 	"iter"
 	"time"
@@ -22,6 +24,8 @@ type WorkspaceConfigs struct {
 	SourceDefinitions SourceDefinitions `json:"sourceDefinitions"`
 	// DestinationDefinitions is a map of destination definitions. The key is a destination definition name.
 	DestinationDefinitions DestinationDefinitions `json:"destinationDefinitions"`
+	// Extra holds the fields that are not modeled above.
+	Extra Extra `json:"-"`
 }
 
 func (wcs *WorkspaceConfigs) Updateables() iter.Seq[diff.UpdateableList[string, diff.UpdateableElement]] {
@@ -84,6 +88,7 @@ type WorkspaceConfig struct {
 	SQLModelVersions                 map[string]*SQLModelVersion        `json:"sqlModelVersions"`
 	SQLModelVersionSourceConnections []*SQLModelVersionSourceConnection `json:"sqlModelVersionSourceConnections"`
 	UpdatedAt                        time.Time                          `json:"updatedAt"`
+	Extra                            Extra                              `json:"-"`
 }
 
 func (wc *WorkspaceConfig) GetUpdatedAt() time.Time { return wc.UpdatedAt }
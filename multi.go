@@ -0,0 +1,229 @@
+package cpsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/poller"
+)
+
+// PollSource is one of the identities (a namespace or a workspace token) polled by a [MultiPoller].
+type PollSource struct {
+	// Name identifies the source in the provenance of workspaces and in conflicts, e.g. the namespace.
+	Name string
+	// Client polls the workspace configs of the source, e.g. a [ControlPlane] returned by [New].
+	Client Client
+}
+
+// WorkspaceConflict is a workspace claimed by more than one source of a [MultiPoller].
+type WorkspaceConflict struct {
+	WorkspaceID string
+	// Sources are the names of the sources claiming the workspace, in priority order. The first one is the one the
+	// workspace is taken from.
+	Sources []string
+}
+
+func (c WorkspaceConflict) String() string {
+	return fmt.Sprintf("workspace %q claimed by sources %s", c.WorkspaceID, strings.Join(c.Sources, ", "))
+}
+
+func (c WorkspaceConflict) equal(other WorkspaceConflict) bool {
+	return c.WorkspaceID == other.WorkspaceID && slices.Equal(c.Sources, other.Sources)
+}
+
+// MultiPollerOption configures a [MultiPoller].
+type MultiPollerOption func(*MultiPoller)
+
+// WithPollerOptions sets the options of the pollers of all the sources, e.g. their polling interval and backoff.
+func WithPollerOptions(opts ...poller.Option[string]) MultiPollerOption {
+	return func(m *MultiPoller) { m.pollerOpts = append(m.pollerOpts, opts...) }
+}
+
+// WithMultiPollerLogger sets the logger used to report conflicts.
+func WithMultiPollerLogger(log logger.Logger) MultiPollerOption {
+	return func(m *MultiPoller) { m.log = log }
+}
+
+// WithOnUpdate registers a function called with the merged workspace configs, and how its workspaces changed, every
+// time the workspace configs of a source are updated. Calls are serialized, and block the updates of all sources
+// until they return.
+func WithOnUpdate(f func(wcs *modelv2.WorkspaceConfigs, changes diff.Changes[string])) MultiPollerOption {
+	return func(m *MultiPoller) { m.onUpdate = f }
+}
+
+// MultiPoller polls the workspace configs of several sources concurrently, each with its own updatedAt cursor and
+// backoff, and merges them into a single cache keyed by workspace ID. Workspaces claimed by more than one source are
+// taken from the first of them in the order the sources were given, and reported by [MultiPoller.Conflicts].
+// Definitions are merged the same way.
+// Its methods are safe for concurrent use.
+type MultiPoller struct {
+	sources    []*pollSource
+	pollerOpts []poller.Option[string]
+	onUpdate   func(*modelv2.WorkspaceConfigs, diff.Changes[string])
+	log        logger.Logger
+
+	mu         sync.RWMutex
+	merged     *modelv2.WorkspaceConfigs
+	provenance map[string]string
+	conflicts  []WorkspaceConflict
+}
+
+type pollSource struct {
+	name    string
+	poller  *poller.WorkspaceConfigsPoller[string]
+	updater diff.Updater[string]
+	cache   *modelv2.WorkspaceConfigs
+}
+
+// NewMultiPoller returns a poller for the given sources, whose names have to be unique.
+func NewMultiPoller(sources []PollSource, opts ...MultiPollerOption) (*MultiPoller, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one source is required")
+	}
+	m := &MultiPoller{
+		log:        logger.NOP,
+		merged:     &modelv2.WorkspaceConfigs{},
+		provenance: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	for _, source := range sources {
+		if source.Name == "" {
+			return nil, errors.New("source name is required")
+		}
+		if source.Client == nil {
+			return nil, fmt.Errorf("source %q: client is required", source.Name)
+		}
+		if slices.ContainsFunc(m.sources, func(s *pollSource) bool { return s.name == source.Name }) {
+			return nil, fmt.Errorf("duplicate source %q", source.Name)
+		}
+		s := &pollSource{name: source.Name, cache: &modelv2.WorkspaceConfigs{}}
+		p, err := poller.NewWorkspaceConfigsPoller(
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return source.Client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				return m.update(s, obj)
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			append(slices.Clone(m.pollerOpts), poller.WithLogger[string](m.log.Withn(logger.NewStringField("source", source.Name))))...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("source %q: %w", source.Name, err)
+		}
+		s.poller = p
+		m.sources = append(m.sources, s)
+	}
+	return m, nil
+}
+
+// Run polls all the sources until the context is cancelled.
+func (m *MultiPoller) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, s := range m.sources {
+		wg.Go(func() { s.poller.Run(ctx) })
+	}
+	wg.Wait()
+}
+
+// WorkspaceConfigs returns the merged workspace configs of all the sources. The returned value is replaced, not
+// modified, by later updates, thus it can be read without further synchronization, but it must not be modified.
+func (m *MultiPoller) WorkspaceConfigs() *modelv2.WorkspaceConfigs {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.merged
+}
+
+// Provenance returns the name of the source a workspace is taken from.
+func (m *MultiPoller) Provenance(workspaceID string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	source, ok := m.provenance[workspaceID]
+	return source, ok
+}
+
+// Conflicts returns the workspaces currently claimed by more than one source, sorted by workspace ID.
+func (m *MultiPoller) Conflicts() []WorkspaceConflict {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.conflicts)
+}
+
+// update applies the workspace configs polled for a source to its own cache, then merges the caches of all sources.
+func (m *MultiPoller) update(s *pollSource, obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updatedAt, updated, err := s.updater.UpdateCache(obj, s.cache)
+	if err != nil || !updated {
+		return updatedAt, updated, err
+	}
+
+	previous := m.merged
+	m.merge()
+	if m.onUpdate != nil {
+		m.onUpdate(m.merged, diff.CompareByUpdatedAt(previous.Workspaces, m.merged.Workspaces))
+	}
+	return updatedAt, updated, nil
+}
+
+func (m *MultiPoller) merge() {
+	merged := &modelv2.WorkspaceConfigs{
+		Workspaces:             make(modelv2.Workspaces),
+		SourceDefinitions:      make(modelv2.SourceDefinitions),
+		DestinationDefinitions: make(modelv2.DestinationDefinitions),
+	}
+	provenance := make(map[string]string)
+	claims := make(map[string][]string)
+	for _, s := range m.sources {
+		for workspaceID, wc := range s.cache.Workspaces {
+			if wc == nil {
+				continue
+			}
+			claims[workspaceID] = append(claims[workspaceID], s.name)
+			if _, ok := merged.Workspaces[workspaceID]; !ok {
+				merged.Workspaces[workspaceID] = wc
+				provenance[workspaceID] = s.name
+			}
+		}
+		for name, def := range s.cache.SourceDefinitions {
+			if _, ok := merged.SourceDefinitions[name]; !ok {
+				merged.SourceDefinitions[name] = def
+			}
+		}
+		for name, def := range s.cache.DestinationDefinitions {
+			if _, ok := merged.DestinationDefinitions[name]; !ok {
+				merged.DestinationDefinitions[name] = def
+			}
+		}
+	}
+
+	var conflicts []WorkspaceConflict
+	for workspaceID, sources := range claims {
+		if len(sources) > 1 {
+			conflicts = append(conflicts, WorkspaceConflict{WorkspaceID: workspaceID, Sources: sources})
+		}
+	}
+	slices.SortFunc(conflicts, func(a, b WorkspaceConflict) int { return strings.Compare(a.WorkspaceID, b.WorkspaceID) })
+	for _, c := range conflicts {
+		if !slices.ContainsFunc(m.conflicts, c.equal) {
+			m.log.Warnn("workspace claimed by more than one source",
+				logger.NewStringField("workspaceId", c.WorkspaceID),
+				logger.NewStringField("sources", strings.Join(c.Sources, ",")),
+			)
+		}
+	}
+
+	m.merged, m.provenance, m.conflicts = merged, provenance, conflicts
+}
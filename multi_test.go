@@ -0,0 +1,141 @@
+package cpsdk
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/poller"
+)
+
+// scriptedClient returns the responses pushed to it, one per call, blocking until the next one is available.
+type scriptedClient struct {
+	Client
+	responses chan string
+
+	mu           sync.Mutex
+	updatedAfter []time.Time
+}
+
+func newScriptedClient() *scriptedClient {
+	return &scriptedClient{responses: make(chan string, 10)}
+}
+
+func (c *scriptedClient) GetWorkspaceConfigs(ctx context.Context, object any, updatedAfter time.Time) error {
+	c.mu.Lock()
+	c.updatedAfter = append(c.updatedAfter, updatedAfter)
+	c.mu.Unlock()
+	select {
+	case body := <-c.responses:
+		return jsonrs.Unmarshal([]byte(body), object)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *scriptedClient) receivedUpdatedAfter() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.updatedAfter)
+}
+
+func TestMultiPoller(t *testing.T) {
+	t.Run("invalid sources", func(t *testing.T) {
+		_, err := NewMultiPoller(nil)
+		require.EqualError(t, err, "at least one source is required")
+		_, err = NewMultiPoller([]PollSource{{Client: newScriptedClient()}})
+		require.EqualError(t, err, "source name is required")
+		_, err = NewMultiPoller([]PollSource{{Name: "a"}})
+		require.EqualError(t, err, `source "a": client is required`)
+		_, err = NewMultiPoller([]PollSource{{Name: "a", Client: newScriptedClient()}, {Name: "a", Client: newScriptedClient()}})
+		require.EqualError(t, err, `duplicate source "a"`)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		type update struct {
+			wcs     *modelv2.WorkspaceConfigs
+			changes diff.Changes[string]
+		}
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			a, b        = newScriptedClient(), newScriptedClient()
+			updates     = make(chan update, 10)
+		)
+		defer cancel()
+
+		m, err := NewMultiPoller(
+			[]PollSource{{Name: "a", Client: a}, {Name: "b", Client: b}},
+			WithPollerOptions(poller.WithPollingInterval[string](time.Millisecond)),
+			WithOnUpdate(func(wcs *modelv2.WorkspaceConfigs, changes diff.Changes[string]) {
+				updates <- update{wcs: wcs, changes: changes}
+			}),
+		)
+		require.NoError(t, err)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.Run(ctx)
+		}()
+
+		a.responses <- `{
+			"workspaces": {
+				"ws-1": {"updatedAt": "2024-01-01T00:00:00Z"},
+				"ws-2": {"updatedAt": "2024-01-01T00:00:00Z"}
+			},
+			"sourceDefinitions": {"Javascript": {"displayName": "JavaScript A"}}
+		}`
+		u := <-updates
+		require.ElementsMatch(t, []string{"ws-1", "ws-2"}, u.changes.Added)
+
+		b.responses <- `{
+			"workspaces": {
+				"ws-2": {"updatedAt": "2024-01-02T00:00:00Z"},
+				"ws-3": {"updatedAt": "2024-01-02T00:00:00Z"}
+			},
+			"sourceDefinitions": {
+				"Javascript": {"displayName": "JavaScript B"},
+				"Android": {"displayName": "Android B"}
+			}
+		}`
+		u = <-updates
+		require.Equal(t, diff.Changes[string]{Added: []string{"ws-3"}}, u.changes)
+		require.Same(t, u.wcs, m.WorkspaceConfigs())
+		require.Len(t, u.wcs.Workspaces, 3)
+		require.Equal(t, "2024-01-01T00:00:00Z", u.wcs.Workspaces["ws-2"].UpdatedAt.Format(time.RFC3339))
+		require.Equal(t, "JavaScript A", u.wcs.SourceDefinitions["Javascript"].DisplayName)
+		require.Equal(t, "Android B", u.wcs.SourceDefinitions["Android"].DisplayName)
+		source, ok := m.Provenance("ws-2")
+		require.True(t, ok)
+		require.Equal(t, "a", source)
+		source, _ = m.Provenance("ws-3")
+		require.Equal(t, "b", source)
+		_, ok = m.Provenance("ws-4")
+		require.False(t, ok)
+		require.Equal(t, []WorkspaceConflict{{WorkspaceID: "ws-2", Sources: []string{"a", "b"}}}, m.Conflicts())
+		require.Equal(t, `workspace "ws-2" claimed by sources a, b`, m.Conflicts()[0].String())
+
+		// ws-2 moving away from a is taken from b, ending the conflict
+		a.responses <- `{"workspaces": {"ws-1": null}}`
+		u = <-updates
+		require.Equal(t, diff.Changes[string]{Changed: []string{"ws-2"}}, u.changes)
+		require.Equal(t, "2024-01-02T00:00:00Z", u.wcs.Workspaces["ws-2"].UpdatedAt.Format(time.RFC3339))
+		source, _ = m.Provenance("ws-2")
+		require.Equal(t, "b", source)
+		require.Empty(t, m.Conflicts())
+
+		cancel()
+		<-done
+
+		// each source is polled with its own cursor
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), a.receivedUpdatedAfter()[1].UTC())
+		require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), b.receivedUpdatedAfter()[1].UTC())
+	})
+}
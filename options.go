@@ -3,8 +3,10 @@ package cpsdk
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/rudderlabs/rudder-cp-sdk/identity"
+	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/base"
 )
 
 type Option func(*ControlPlane) error
@@ -33,12 +35,49 @@ func WithNamespaceIdentity(namespace, secret string) Option {
 }
 
 func WithBaseUrl(baseUrl string) Option {
+	return WithBaseUrls(baseUrl)
+}
+
+// WithBaseUrls sets the base URL of the control plane along with fallback ones. Requests failing with a connection
+// error or a 5xx status code are sent to the next base URL, which keeps serving the following requests until the
+// primary one is available again, see [WithPrimaryProbeInterval].
+func WithBaseUrls(primary string, fallbacks ...string) Option {
 	return func(cp *ControlPlane) error {
-		u, err := url.Parse(baseUrl)
+		u, err := url.Parse(primary)
 		if err != nil {
 			return fmt.Errorf("invalid base url: %w", err)
 		}
+		fallbackUrls := make([]*url.URL, 0, len(fallbacks))
+		for _, fallback := range fallbacks {
+			fu, err := url.Parse(fallback)
+			if err != nil {
+				return fmt.Errorf("invalid fallback base url: %w", err)
+			}
+			fallbackUrls = append(fallbackUrls, fu)
+		}
 		cp.config.baseUrl = u
+		cp.config.fallbackUrls = fallbackUrls
+		return nil
+	}
+}
+
+// WithPrimaryProbeInterval sets how often the primary base URL is tried again after failing over to a fallback one.
+// If not set, [DefaultPrimaryProbeInterval] applies.
+func WithPrimaryProbeInterval(d time.Duration) Option {
+	return func(cp *ControlPlane) error {
+		if d < 0 {
+			return fmt.Errorf("invalid primary probe interval: %s", d)
+		}
+		cp.config.probeInterval = &d
+		return nil
+	}
+}
+
+// WithOnEndpointResponse registers a function called after every request sent to a base URL, with the base URL and
+// the error returned by the request, if any. It is only called when fallback base URLs are set, see [WithBaseUrls].
+func WithOnEndpointResponse(f func(baseUrl string, err error)) Option {
+	return func(cp *ControlPlane) error {
+		cp.config.onEndpointResponse = f
 		return nil
 	}
 }
@@ -57,6 +96,85 @@ func WithSecrets(s Secrets) Option {
 	}
 }
 
+// WithRateLimit limits the rate of the requests sent to the control plane to requestsPerSecond on average, with bursts
+// of up to burst requests. Requests exceeding the limit wait for their turn, or for their context to be done.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(cp *ControlPlane) error {
+		if requestsPerSecond <= 0 || burst < 1 {
+			return fmt.Errorf("invalid rate limit: %v requests per second with a burst of %d", requestsPerSecond, burst)
+		}
+		cp.config.rateLimiter = base.NewRateLimiter(requestsPerSecond, burst)
+		return nil
+	}
+}
+
+// WithMaxInFlight caps the number of concurrent requests sent to the control plane. A request is in flight until its
+// response is fully read, other requests waiting for it, or for their context to be done.
+func WithMaxInFlight(n int) Option {
+	return func(cp *ControlPlane) error {
+		if n < 1 {
+			return fmt.Errorf("invalid max in flight requests: %d", n)
+		}
+		cp.config.inFlight = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithRequestCoalescing makes concurrent GetWorkspaceConfigs calls with the same updatedAfter share a single request
+// to the control plane, and the workspace configs decoded from its response, each caller getting its own deep copy.
+// Only calls decoding into [modelv2.WorkspaceConfigs] are coalesced, their object being overwritten rather than
+// merged with the response. The shared request is cancelled once the contexts of all its callers are done.
+func WithRequestCoalescing() Option {
+	return func(cp *ControlPlane) error {
+		cp.config.coalesce = true
+		return nil
+	}
+}
+
+// WithMaxResponseSize limits the size of the response bodies of the control plane, larger ones failing with
+// [ErrResponseTooLarge] instead of being decoded.
+func WithMaxResponseSize(maxBytes int64) Option {
+	return func(cp *ControlPlane) error {
+		if maxBytes < 1 {
+			return fmt.Errorf("invalid max response size: %d", maxBytes)
+		}
+		cp.config.maxResponseSize = maxBytes
+		return nil
+	}
+}
+
+// WithMaxDecodeDuration limits the time spent reading and decoding a response body once its headers are received,
+// decoding failing with [ErrDecodeTimeout] past it.
+func WithMaxDecodeDuration(d time.Duration) Option {
+	return func(cp *ControlPlane) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid max decode duration: %s", d)
+		}
+		cp.config.maxDecodeDuration = d
+		return nil
+	}
+}
+
+// WithContentTypeCheck makes successful responses whose content type is not JSON fail with
+// [UnexpectedContentTypeError], e.g. the HTML pages of a misconfigured proxy.
+func WithContentTypeCheck() Option {
+	return func(cp *ControlPlane) error {
+		cp.config.checkContentType = true
+		return nil
+	}
+}
+
+// WithErrorBodyLimit sets the number of bytes of the response body kept by [UnexpectedStatusCodeError], 1MB by default.
+func WithErrorBodyLimit(maxBytes int64) Option {
+	return func(cp *ControlPlane) error {
+		if maxBytes < 1 {
+			return fmt.Errorf("invalid error body limit: %d", maxBytes)
+		}
+		cp.config.errorBodyLimit = maxBytes
+		return nil
+	}
+}
+
 func WithRequestDoer(reqDoer RequestDoer) Option {
 	return func(cp *ControlPlane) error {
 		cp.config.httpClient = reqDoer
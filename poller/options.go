@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/rudderlabs/rudder-go-kit/logger"
+
+	"github.com/rudderlabs/rudder-cp-sdk/diff"
 )
 
 type Option[K comparable] func(*WorkspaceConfigsPoller[K])
@@ -40,3 +42,54 @@ func WithPollingBackoffMultiplier[K comparable](m float64) Option[K] {
 func WithOnResponse[K comparable](f func(context.Context, bool, error)) Option[K] {
 	return func(p *WorkspaceConfigsPoller[K]) { p.onResponse = f }
 }
+
+// WithTransformer makes the poller modify the workspace configs it gets before passing them to the handler, e.g. to
+// only keep the settings of one region:
+//
+//	poller.WithTransformer[string](func(obj diff.UpdateableObject[string]) error {
+//		obj.(*modelv2.WorkspaceConfigs).ProjectToRegion(modelv2.RegionEU)
+//		return nil
+//	})
+//
+// An error returned by the transformer fails the poll, like a getter error does.
+func WithTransformer[K comparable](f func(diff.UpdateableObject[K]) error) Option[K] {
+	return func(p *WorkspaceConfigsPoller[K]) { p.transformer = f }
+}
+
+// Filter decides whether the poller keeps an updated element (e.g. a workspace config) of the updateable list of the
+// given type, returning the element to pass to the handler instead, or nil to pass it as is. The element can be
+// modified, e.g. to drop some of its entities.
+type Filter[K comparable] func(listType string, key K, element diff.UpdateableElement) (diff.UpdateableElement, bool)
+
+// WithFilter makes the poller drop the elements rejected by the filter before passing the workspace configs to the
+// handler, after the transformer if any, e.g. to only keep some workspaces and their enabled sources:
+//
+//	poller.WithFilter[string](func(_, workspaceID string, element diff.UpdateableElement) (diff.UpdateableElement, bool) {
+//		if !handled(workspaceID) {
+//			return nil, false
+//		}
+//		wc := element.(*modelv2.WorkspaceConfig)
+//		maps.DeleteFunc(wc.Sources, func(_ string, s *modelv2.Source) bool { return !s.Enabled })
+//		return wc, true
+//	})
+//
+// Elements reported as not updated are dropped if their last received version was rejected. Elements that start
+// being rejected are thus removed from the cache of the handler, and elements that stop being rejected are added to
+// it again. The updatedAt the poller resumes from takes the rejected elements into account too, so that they are not
+// fetched again. A response whose elements are all rejected is passed to the handler, emptied, only to remove the
+// elements accepted so far, otherwise it is treated as no update.
+func WithFilter[K comparable](f Filter[K]) Option[K] {
+	return func(p *WorkspaceConfigsPoller[K]) { p.filter.fn = f }
+}
+
+// WithInconsistencyRecovery makes the poller recover from the cache inconsistencies reported by the handler as a
+// [diff.InconsistencyError] (e.g. a workspace reported as not updated that is missing from the cache) instead of
+// retrying with the same updatedAt forever.
+// If getter is not nil, only the inconsistent elements are fetched again through it, otherwise, or if that fails,
+// all workspace configs are fetched again with a zero updatedAfter.
+func WithInconsistencyRecovery[K comparable](getter WorkspaceConfigsByKeysGetter[K]) Option[K] {
+	return func(p *WorkspaceConfigsPoller[K]) {
+		p.recovery.enabled = true
+		p.recovery.getter = getter
+	}
+}
@@ -2,7 +2,9 @@ package poller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -17,6 +19,10 @@ type WorkspaceConfigsGetter[K comparable] func(ctx context.Context, l diff.Updat
 
 type WorkspaceConfigsHandler[K comparable] func(obj diff.UpdateableObject[K]) (time.Time, bool, error)
 
+// WorkspaceConfigsByKeysGetter fetches the latest version of the elements with the given keys (e.g. workspace IDs),
+// regardless of when they were last updated.
+type WorkspaceConfigsByKeysGetter[K comparable] func(ctx context.Context, l diff.UpdateableObject[K], keys []K) error
+
 // WorkspaceConfigsPoller periodically polls for new workspace configs and runs a handler on them.
 type WorkspaceConfigsPoller[K comparable] struct {
 	getter      WorkspaceConfigsGetter[K]
@@ -25,6 +31,7 @@ type WorkspaceConfigsPoller[K comparable] struct {
 	interval    time.Duration
 	updatedAt   time.Time
 	onResponse  func(context.Context, bool, error)
+	transformer func(diff.UpdateableObject[K]) error
 	backoff     struct {
 		initialInterval time.Duration
 		maxInterval     time.Duration
@@ -32,6 +39,22 @@ type WorkspaceConfigsPoller[K comparable] struct {
 		maxRetries      uint64
 		multiplier      float64
 	}
+	recovery struct {
+		enabled    bool
+		getter     WorkspaceConfigsByKeysGetter[K]
+		recoveries atomic.Uint64
+	}
+	filter struct {
+		fn Filter[K]
+		// excluded holds the keys of the elements dropped by the filter, per updateable list type, so that they
+		// can be dropped too when they are reported as not updated.
+		excluded map[string]map[K]struct{}
+		// latestUpdatedAt is the latest updatedAt of the elements received by the current poll, filtered or not.
+		latestUpdatedAt time.Time
+		// accepted tells whether the handler was last passed any element, which a response emptied by the filter
+		// has to remove from its cache.
+		accepted bool
+	}
 	log logger.Logger
 }
 
@@ -115,23 +138,212 @@ func (p *WorkspaceConfigsPoller[K]) Run(ctx context.Context) {
 	}
 }
 
+// Recoveries returns the number of cache inconsistencies the poller has recovered from so far.
+// It is always zero unless [WithInconsistencyRecovery] is used.
+func (p *WorkspaceConfigsPoller[K]) Recoveries() uint64 {
+	return p.recovery.recoveries.Load()
+}
+
 func (p *WorkspaceConfigsPoller[K]) poll(ctx context.Context) (bool, error) {
 	p.log.Debugn("polling for workspace configs", logger.NewTimeField("updatedAt", p.updatedAt))
 
+	p.filter.latestUpdatedAt = time.Time{}
 	response := p.constructor()
-	err := p.getter(ctx, response, p.updatedAt)
-	if err != nil {
+	if err := p.get(ctx, response, p.updatedAt); err != nil {
 		return false, fmt.Errorf("failed to get updated workspace configs: %w", err)
 	}
 
+	// A response emptied by the filter is only passed to the handler if it removes the elements accepted so far,
+	// otherwise the handler would reject it as empty, while it only means that nothing the handler cares about
+	// changed.
+	if p.filter.fn != nil && !p.filter.accepted && isEmpty(response) {
+		if p.filter.latestUpdatedAt.After(p.updatedAt) {
+			p.updatedAt = p.filter.latestUpdatedAt
+		}
+		return false, nil
+	}
+
 	updatedAt, updated, err := p.handler(response)
+	var inconsistency *diff.InconsistencyError[K]
+	if err != nil && p.recovery.enabled && errors.As(err, &inconsistency) {
+		updatedAt, updated, err = p.recover(ctx, response, inconsistency)
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to handle workspace configs: %w", err)
 	}
+	p.filter.accepted = !isEmpty(response)
 
+	// The handler doesn't see the elements dropped by the filter, thus the updatedAt it returns can lag behind the
+	// one of the unfiltered stream, while these elements don't have to be fetched again either.
+	if p.filter.fn != nil {
+		for _, t := range []time.Time{p.filter.latestUpdatedAt, p.updatedAt} {
+			if t.After(updatedAt) {
+				updatedAt = t
+			}
+		}
+	}
 	if !updatedAt.IsZero() {
 		p.updatedAt = updatedAt
 	}
 
 	return updated, nil
 }
+
+// recover handles a response again after an inconsistency was detected. If a getter by keys was provided, only the
+// inconsistent elements are fetched again and patched into the response, otherwise, or if that fails, the whole
+// object is fetched again with a zero updatedAfter.
+func (p *WorkspaceConfigsPoller[K]) recover(
+	ctx context.Context, response diff.UpdateableObject[K], inconsistency *diff.InconsistencyError[K],
+) (time.Time, bool, error) {
+	p.log.Warnn("recovering from workspace configs cache inconsistency", obskit.Error(inconsistency))
+
+	if p.recovery.getter != nil {
+		updatedAt, updated, err := p.recoverKeys(ctx, response, inconsistency.AllKeys())
+		if err == nil {
+			p.recovery.recoveries.Add(1)
+			return updatedAt, updated, nil
+		}
+		p.log.Warnn("failed to refetch inconsistent workspace configs, falling back to a full refetch", obskit.Error(err))
+	}
+
+	response = p.constructor()
+	if err := p.get(ctx, response, time.Time{}); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get all workspace configs: %w", err)
+	}
+	updatedAt, updated, err := p.handler(response)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	p.recovery.recoveries.Add(1)
+	return updatedAt, updated, nil
+}
+
+func (p *WorkspaceConfigsPoller[K]) recoverKeys(
+	ctx context.Context, response diff.UpdateableObject[K], keys []K,
+) (time.Time, bool, error) {
+	partial := p.constructor()
+	if err := p.recovery.getter(ctx, partial, keys); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get workspace configs by keys: %w", err)
+	}
+	if err := p.transform(partial); err != nil {
+		return time.Time{}, false, err
+	}
+	p.applyFilter(partial, false)
+	for pl := range partial.Updateables() {
+		for rl := range response.Updateables() {
+			if rl.Type() != pl.Type() {
+				continue
+			}
+			for k, v := range pl.List() {
+				rl.SetElementByKey(k, v)
+			}
+			break
+		}
+	}
+	// the refetched elements rejected by the filter are still reported as not updated by the response
+	p.dropExcluded(response)
+	return p.handler(response)
+}
+
+// get fetches the workspace configs updated after updatedAfter, applying the transformer and the filter to them if
+// any.
+func (p *WorkspaceConfigsPoller[K]) get(ctx context.Context, response diff.UpdateableObject[K], updatedAfter time.Time) error {
+	if err := p.getter(ctx, response, updatedAfter); err != nil {
+		return err
+	}
+	if err := p.transform(response); err != nil {
+		return err
+	}
+	p.applyFilter(response, true)
+	return nil
+}
+
+func (p *WorkspaceConfigsPoller[K]) transform(response diff.UpdateableObject[K]) error {
+	if p.transformer == nil {
+		return nil
+	}
+	if err := p.transformer(response); err != nil {
+		return fmt.Errorf("failed to transform workspace configs: %w", err)
+	}
+	return nil
+}
+
+// applyFilter drops the elements of the response rejected by the filter, if any, together with the elements reported
+// as not updated whose last received version was rejected. Elements accepted again are passed on as new ones.
+// A complete response lists all the elements, so the keys missing from it are forgotten, while a partial one (e.g.
+// refetched by keys) only lists some of them.
+func (p *WorkspaceConfigsPoller[K]) applyFilter(response diff.UpdateableObject[K], complete bool) {
+	if p.filter.fn == nil {
+		return
+	}
+	if p.filter.excluded == nil {
+		p.filter.excluded = make(map[string]map[K]struct{})
+	}
+	for l := range response.Updateables() {
+		excluded := p.filter.excluded[l.Type()]
+		if excluded == nil {
+			excluded = make(map[K]struct{})
+			p.filter.excluded[l.Type()] = excluded
+		}
+		seen := make(map[K]struct{}, l.Length())
+		for k, v := range l.List() {
+			seen[k] = struct{}{}
+			if v.IsNil() {
+				continue
+			}
+			if v.GetUpdatedAt().After(p.filter.latestUpdatedAt) {
+				p.filter.latestUpdatedAt = v.GetUpdatedAt()
+			}
+			filtered, keep := p.filter.fn(l.Type(), k, v)
+			if !keep {
+				excluded[k] = struct{}{}
+				continue
+			}
+			delete(excluded, k)
+			if filtered != nil {
+				l.SetElementByKey(k, filtered)
+			}
+		}
+		if complete {
+			for k := range excluded {
+				if _, ok := seen[k]; !ok {
+					delete(excluded, k)
+				}
+			}
+		}
+	}
+	p.dropExcluded(response)
+}
+
+// isEmpty returns true if none of the updateable lists of the response has any element.
+func isEmpty[K comparable](response diff.UpdateableObject[K]) bool {
+	for l := range response.Updateables() {
+		if l.Length() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// dropExcluded removes the elements whose last received version was rejected by the filter from the response.
+func (p *WorkspaceConfigsPoller[K]) dropExcluded(response diff.UpdateableObject[K]) {
+	for l := range response.Updateables() {
+		excluded := p.filter.excluded[l.Type()]
+		if len(excluded) == 0 {
+			continue
+		}
+		kept := make(map[K]diff.UpdateableElement, l.Length())
+		for k, v := range l.List() {
+			if _, ok := excluded[k]; !ok {
+				kept[k] = v
+			}
+		}
+		if len(kept) == l.Length() {
+			continue
+		}
+		l.Reset()
+		for k, v := range kept {
+			l.SetElementByKey(k, v)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package poller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -241,6 +244,465 @@ func TestPoller(t *testing.T) {
 	})
 }
 
+func TestPollerInconsistencyRecovery(t *testing.T) {
+	var (
+		wc1UpdatedAt = time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+		wc2UpdatedAt = time.Date(2009, 11, 18, 20, 34, 58, 651387237, time.UTC)
+		wc3UpdatedAt = time.Date(2009, 11, 19, 20, 34, 58, 651387238, time.UTC)
+	)
+
+	// runRecoveryPoller polls until the handler has been called n times, then returns the poller and the cache
+	runRecoveryPoller := func(
+		t *testing.T, client *mockClient, n int, opts ...Option[string],
+	) (*WorkspaceConfigsPoller[string], *modelv2.WorkspaceConfigs) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cache := &modelv2.WorkspaceConfigs{}
+		updater := &diff.Updater[string]{}
+		var calls int
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				if calls++; calls == n {
+					cancel()
+				}
+				return updater.UpdateCache(obj, cache)
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			opts...,
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+		return p, cache
+	}
+
+	initialCall := func() clientCall {
+		return clientCall{
+			dataToBeReturned: &modelv2.WorkspaceConfigs{
+				Workspaces: map[string]*modelv2.WorkspaceConfig{
+					"wc-1": {UpdatedAt: wc1UpdatedAt},
+					"wc-2": {UpdatedAt: wc2UpdatedAt},
+				},
+			},
+			expectedUpdatedAt: time.Time{},
+		}
+	}
+	inconsistentCall := func() clientCall {
+		return clientCall{ // wc-3 is reported as not updated although it was never received
+			dataToBeReturned: &modelv2.WorkspaceConfigs{
+				Workspaces: map[string]*modelv2.WorkspaceConfig{"wc-1": nil, "wc-2": nil, "wc-3": nil},
+			},
+			expectedUpdatedAt: wc2UpdatedAt,
+		}
+	}
+	noUpdatesCall := func() clientCall {
+		return clientCall{
+			dataToBeReturned: &modelv2.WorkspaceConfigs{
+				Workspaces: map[string]*modelv2.WorkspaceConfig{"wc-1": nil, "wc-2": nil, "wc-3": nil},
+			},
+			expectedUpdatedAt: wc3UpdatedAt,
+		}
+	}
+	requireRecovered := func(t *testing.T, p *WorkspaceConfigsPoller[string], cache *modelv2.WorkspaceConfigs) {
+		t.Helper()
+		require.EqualValues(t, 1, p.Recoveries())
+		require.Len(t, cache.Workspaces, 3)
+		require.Equal(t, wc1UpdatedAt, cache.Workspaces["wc-1"].UpdatedAt)
+		require.Equal(t, wc2UpdatedAt, cache.Workspaces["wc-2"].UpdatedAt)
+		require.Equal(t, wc3UpdatedAt, cache.Workspaces["wc-3"].UpdatedAt)
+	}
+
+	t.Run("should not recover unless enabled", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{initialCall(), inconsistentCall(), inconsistentCall()}}
+		p, cache := runRecoveryPoller(t, client, 3)
+		require.Zero(t, p.Recoveries())
+		require.Len(t, cache.Workspaces, 2)
+	})
+
+	t.Run("should recover with a full refetch", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{
+			initialCall(),
+			inconsistentCall(),
+			{ // full refetch
+				dataToBeReturned: &modelv2.WorkspaceConfigs{
+					Workspaces: map[string]*modelv2.WorkspaceConfig{
+						"wc-1": {UpdatedAt: wc1UpdatedAt},
+						"wc-2": {UpdatedAt: wc2UpdatedAt},
+						"wc-3": {UpdatedAt: wc3UpdatedAt},
+					},
+				},
+				expectedUpdatedAt: time.Time{},
+			},
+			noUpdatesCall(),
+		}}
+		p, cache := runRecoveryPoller(t, client, 4, WithInconsistencyRecovery[string](nil))
+		require.Equal(t, 4, client.nextCall)
+		requireRecovered(t, p, cache)
+	})
+
+	t.Run("should recover by refetching the inconsistent workspaces only", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{initialCall(), inconsistentCall(), noUpdatesCall()}}
+		var requestedKeys []string
+		p, cache := runRecoveryPoller(t, client, 4, WithInconsistencyRecovery[string](
+			func(_ context.Context, l diff.UpdateableObject[string], keys []string) error {
+				requestedKeys = keys
+				l.(*modelv2.WorkspaceConfigs).Workspaces = map[string]*modelv2.WorkspaceConfig{
+					"wc-3": {UpdatedAt: wc3UpdatedAt},
+				}
+				return nil
+			},
+		))
+		require.Equal(t, []string{"wc-3"}, requestedKeys)
+		require.Equal(t, 3, client.nextCall)
+		requireRecovered(t, p, cache)
+	})
+
+	t.Run("should fall back to a full refetch if refetching by keys fails", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{
+			initialCall(),
+			inconsistentCall(),
+			{ // full refetch
+				dataToBeReturned: &modelv2.WorkspaceConfigs{
+					Workspaces: map[string]*modelv2.WorkspaceConfig{
+						"wc-1": {UpdatedAt: wc1UpdatedAt},
+						"wc-2": {UpdatedAt: wc2UpdatedAt},
+						"wc-3": {UpdatedAt: wc3UpdatedAt},
+					},
+				},
+				expectedUpdatedAt: time.Time{},
+			},
+			noUpdatesCall(),
+		}}
+		p, cache := runRecoveryPoller(t, client, 4, WithInconsistencyRecovery[string](
+			func(_ context.Context, _ diff.UpdateableObject[string], _ []string) error {
+				return errors.New("not available")
+			},
+		))
+		require.Equal(t, 4, client.nextCall)
+		requireRecovered(t, p, cache)
+	})
+}
+
+func TestPollerTransformer(t *testing.T) {
+	wcUpdatedAt := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+	response := func() clientCall {
+		return clientCall{
+			dataToBeReturned: &modelv2.WorkspaceConfigs{
+				Workspaces: map[string]*modelv2.WorkspaceConfig{
+					"wc-1": {
+						UpdatedAt: wcUpdatedAt,
+						Destinations: map[string]*modelv2.Destination{
+							"dst-1": {
+								Config:     map[string]any{"endpoint": "us.example.com"},
+								RevisionID: "rev-1",
+								Regions: []*modelv2.DestinationRegion{
+									{Region: modelv2.RegionEU, Config: map[string]any{"endpoint": "eu.example.com"}, RevisionID: "rev-eu-1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("should pass the transformed response to the handler", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := &mockClient{calls: []clientCall{response()}}
+		var handled *modelv2.WorkspaceConfigs
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				handled = obj.(*modelv2.WorkspaceConfigs)
+				cancel()
+				return wcUpdatedAt, true, nil
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			WithTransformer[string](func(obj diff.UpdateableObject[string]) error {
+				obj.(*modelv2.WorkspaceConfigs).ProjectToRegion(modelv2.RegionEU)
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+
+		require.Equal(t, &modelv2.Destination{
+			Config:     map[string]any{"endpoint": "eu.example.com"},
+			RevisionID: "rev-eu-1",
+		}, handled.Workspaces["wc-1"].Destinations["dst-1"])
+	})
+
+	t.Run("should fail the poll if the transformer fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := &mockClient{calls: []clientCall{response()}}
+		var pollErr error
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				t.Error("handler should not be called")
+				return time.Time{}, false, nil
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			WithTransformer[string](func(diff.UpdateableObject[string]) error {
+				return errors.New("transformer failed")
+			}),
+			WithOnResponse[string](func(_ context.Context, _ bool, err error) {
+				if pollErr == nil {
+					pollErr = err
+				}
+				cancel()
+			}),
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+		require.ErrorContains(t, pollErr, "transformer failed")
+	})
+}
+
+func TestPollerFilter(t *testing.T) {
+	var (
+		t1 = time.Date(2009, 11, 17, 20, 34, 58, 1, time.UTC)
+		t2 = time.Date(2009, 11, 18, 20, 34, 58, 2, time.UTC)
+		t3 = time.Date(2009, 11, 19, 20, 34, 58, 3, time.UTC)
+		t4 = time.Date(2009, 11, 20, 20, 34, 58, 4, time.UTC)
+	)
+	workspace := func(plan string, updatedAt time.Time) *modelv2.WorkspaceConfig {
+		return &modelv2.WorkspaceConfig{
+			UpdatedAt: updatedAt,
+			Sources: map[string]*modelv2.Source{
+				"enabled":  {Enabled: true},
+				"disabled": {Enabled: false},
+			},
+			Extra: modelv2.Extra{"plan": json.RawMessage(`"` + plan + `"`)},
+		}
+	}
+	filter := WithFilter[string](func(listType, _ string, element diff.UpdateableElement) (diff.UpdateableElement, bool) {
+		require.Equal(t, "Workspaces", listType)
+		wc := element.(*modelv2.WorkspaceConfig)
+		var plan string
+		if _, err := wc.Extra.Decode("plan", &plan); err != nil || plan != "enterprise" {
+			return nil, false
+		}
+		maps.DeleteFunc(wc.Sources, func(_ string, s *modelv2.Source) bool { return !s.Enabled })
+		return wc, true
+	})
+
+	t.Run("should drop rejected workspaces and keep the cursor of the unfiltered stream", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{
+			{
+				dataToBeReturned: &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{
+					"wc-1": workspace("enterprise", t1),
+					"wc-2": workspace("free", t2),
+				}},
+				expectedUpdatedAt: time.Time{},
+			},
+			{ // nothing changed, wc-2 was rejected and must not be reported as inconsistent
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": nil, "wc-2": nil}},
+				expectedUpdatedAt: t2,
+			},
+			{ // wc-1 moves out of the filter, wc-2 moves in
+				dataToBeReturned: &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{
+					"wc-1": workspace("free", t3),
+					"wc-2": workspace("enterprise", t4),
+				}},
+				expectedUpdatedAt: t2,
+			},
+			{ // wc-1 is deleted
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-2": nil}},
+				expectedUpdatedAt: t4,
+			},
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var (
+			cache   = &modelv2.WorkspaceConfigs{}
+			updater = &diff.Updater[string]{}
+			changes []diff.Changes[string]
+			caches  []modelv2.Workspaces
+			pollErr error
+		)
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				updatedAt, updated, err := updater.UpdateCache(obj, cache)
+				if err == nil {
+					changes = append(changes, updater.Changes()["Workspaces"])
+					caches = append(caches, maps.Clone(cache.Workspaces))
+				}
+				if len(changes) == 4 {
+					cancel()
+				}
+				return updatedAt, updated, err
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			filter,
+			WithOnResponse[string](func(ctx context.Context, _ bool, err error) {
+				if err != nil && ctx.Err() == nil && pollErr == nil {
+					pollErr = err
+					cancel()
+				}
+			}),
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+		require.NoError(t, pollErr)
+		require.Equal(t, 4, client.nextCall)
+
+		require.Equal(t, []diff.Changes[string]{
+			{Added: []string{"wc-1"}},
+			{},
+			{Added: []string{"wc-2"}, Removed: []string{"wc-1"}},
+			{},
+		}, changes)
+		require.Equal(t, []string{"wc-1"}, slices.Collect(maps.Keys(caches[0])))
+		require.Equal(t, map[string]*modelv2.Source{"enabled": {Enabled: true}}, caches[0]["wc-1"].Sources)
+		require.Equal(t, []string{"wc-2"}, slices.Collect(maps.Keys(caches[2])))
+		require.Equal(t, map[string]*modelv2.Source{"enabled": {Enabled: true}}, caches[2]["wc-2"].Sources)
+		require.Equal(t, caches[2], caches[3])
+		require.Equal(t, t4, p.updatedAt)
+	})
+
+	t.Run("should remove the only accepted workspace once it moves out of the filter", func(t *testing.T) {
+		// a workspace identity only ever receives its own workspace
+		client := &mockClient{calls: []clientCall{
+			{
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": workspace("enterprise", t1)}},
+				expectedUpdatedAt: time.Time{},
+			},
+			{ // wc-1 moves out of the filter, emptying the response
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": workspace("free", t2)}},
+				expectedUpdatedAt: t1,
+			},
+			{ // nothing changed, there is nothing left to remove
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": nil}},
+				expectedUpdatedAt: t2,
+			},
+			{ // wc-1 moves in again
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": workspace("enterprise", t3)}},
+				expectedUpdatedAt: t2,
+			},
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var (
+			cache     = &modelv2.WorkspaceConfigs{}
+			updater   = &diff.Updater[string]{}
+			changes   []diff.Changes[string]
+			responses []bool
+			pollErr   error
+		)
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				updatedAt, updated, err := updater.UpdateCache(obj, cache)
+				if err == nil {
+					changes = append(changes, updater.Changes()["Workspaces"])
+				}
+				return updatedAt, updated, err
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			filter,
+			WithOnResponse[string](func(ctx context.Context, updated bool, err error) {
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					pollErr = err
+					cancel()
+					return
+				}
+				if responses = append(responses, updated); len(responses) == 4 {
+					cancel()
+				}
+			}),
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+		require.NoError(t, pollErr)
+		require.Equal(t, 4, client.nextCall)
+
+		require.Equal(t, []bool{true, true, false, true}, responses)
+		require.Equal(t, []diff.Changes[string]{
+			{Added: []string{"wc-1"}},
+			{Removed: []string{"wc-1"}},
+			{Added: []string{"wc-1"}},
+		}, changes)
+		require.Equal(t, []string{"wc-1"}, slices.Collect(maps.Keys(cache.Workspaces)))
+		require.Equal(t, t3, p.updatedAt)
+	})
+
+	t.Run("should drop rejected workspaces refetched by keys", func(t *testing.T) {
+		client := &mockClient{calls: []clientCall{
+			{
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": workspace("enterprise", t1)}},
+				expectedUpdatedAt: time.Time{},
+			},
+			{ // wc-2 is reported as not updated although it was never received
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": nil, "wc-2": nil}},
+				expectedUpdatedAt: t1,
+			},
+			{
+				dataToBeReturned:  &modelv2.WorkspaceConfigs{Workspaces: modelv2.Workspaces{"wc-1": nil, "wc-2": nil}},
+				expectedUpdatedAt: t2,
+			},
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cache := &modelv2.WorkspaceConfigs{}
+		updater := &diff.Updater[string]{}
+		var calls int
+		p, err := newWorkspaceConfigsPoller[string](
+			func(ctx context.Context, l diff.UpdateableObject[string], updatedAfter time.Time) error {
+				return client.GetWorkspaceConfigs(ctx, l, updatedAfter)
+			},
+			func(obj diff.UpdateableObject[string]) (time.Time, bool, error) {
+				if calls++; calls == 4 {
+					cancel()
+				}
+				return updater.UpdateCache(obj, cache)
+			},
+			func() diff.UpdateableObject[string] { return &modelv2.WorkspaceConfigs{} },
+			logger.NOP,
+			filter,
+			WithInconsistencyRecovery[string](func(_ context.Context, l diff.UpdateableObject[string], _ []string) error {
+				l.(*modelv2.WorkspaceConfigs).Workspaces = modelv2.Workspaces{"wc-2": workspace("free", t2)}
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+		p.Run(ctx)
+
+		require.Equal(t, 3, client.nextCall)
+		require.EqualValues(t, 1, p.Recoveries())
+		require.Equal(t, []string{"wc-1"}, slices.Collect(maps.Keys(cache.Workspaces)))
+		require.Equal(t, t2, p.updatedAt)
+	})
+}
+
 func runTestPoller(
 	t *testing.T,
 	ctx context.Context,
@@ -295,14 +757,15 @@ func newWorkspaceConfigsPoller[K comparable](
 	handler WorkspaceConfigsHandler[K],
 	constructor func() diff.UpdateableObject[K],
 	log logger.Logger,
+	opts ...Option[K],
 ) (*WorkspaceConfigsPoller[K], error) {
-	return NewWorkspaceConfigsPoller(getter, handler, constructor,
+	return NewWorkspaceConfigsPoller(getter, handler, constructor, append([]Option[K]{
 		WithLogger[K](log.Child("poller")),
 		WithPollingInterval[K](time.Nanosecond),
 		WithPollingBackoffInitialInterval[K](time.Nanosecond),
 		WithPollingBackoffMaxInterval[K](time.Nanosecond),
 		WithPollingBackoffMultiplier[K](1),
-	)
+	}, opts...)...)
 }
 
 func getLatestUpdatedAt() func(list diff.UpdateableObject[string]) time.Time {
@@ -13,11 +13,16 @@ import (
 	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/base"
 	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/namespace"
 	"github.com/rudderlabs/rudder-cp-sdk/internal/clients/workspace"
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
 )
 
 const (
 	defaultWorkspaceIdentityBaseURL = "https://api.rudderstack.com"
 	defaultNamespaceIdentityBaseURL = "https://dp.api.rudderstack.com"
+
+	// DefaultPrimaryProbeInterval is how often the primary base URL is tried again after failing over to a fallback
+	// one, see [WithBaseUrls].
+	DefaultPrimaryProbeInterval = time.Minute
 )
 
 // Secrets determines how account secrets are returned by the workspace configs endpoints.
@@ -33,11 +38,21 @@ const (
 type ControlPlane struct {
 	Client
 	config struct {
-		baseUrl           *url.URL
-		workspaceIdentity *identity.Workspace
-		namespaceIdentity *identity.Namespace
-		httpClient        RequestDoer
-		secrets           Secrets
+		baseUrl            *url.URL
+		fallbackUrls       []*url.URL
+		probeInterval      *time.Duration
+		onEndpointResponse func(baseUrl string, err error)
+		workspaceIdentity  *identity.Workspace
+		namespaceIdentity  *identity.Namespace
+		httpClient         RequestDoer
+		secrets            Secrets
+		rateLimiter        *base.RateLimiter
+		inFlight           chan struct{}
+		coalesce           bool
+		maxResponseSize    int64
+		maxDecodeDuration  time.Duration
+		checkContentType   bool
+		errorBodyLimit     int64
 	}
 }
 
@@ -68,27 +83,59 @@ func New(options ...Option) (*ControlPlane, error) {
 	}
 	// set client based on identity
 	if cp.config.workspaceIdentity != nil {
-		baseUrl := cp.config.baseUrl
-		if baseUrl == nil {
-			baseUrl, _ = url.Parse(defaultWorkspaceIdentityBaseURL)
-		}
 		cp.Client = &workspace.Client{
-			Client:   &base.Client{HTTPClient: cp.config.httpClient, BaseURL: baseUrl},
+			Client:   cp.baseClient(defaultWorkspaceIdentityBaseURL),
 			Identity: cp.config.workspaceIdentity,
 			Secrets:  string(cp.config.secrets),
 		}
 	} else if cp.config.namespaceIdentity != nil {
-		baseUrl := cp.config.baseUrl
-		if baseUrl == nil {
-			baseUrl, _ = url.Parse(defaultNamespaceIdentityBaseURL)
-		}
 		cp.Client = &namespace.Client{
-			Client:   &base.Client{HTTPClient: cp.config.httpClient, BaseURL: baseUrl},
+			Client:   cp.baseClient(defaultNamespaceIdentityBaseURL),
 			Identity: cp.config.namespaceIdentity,
 			Secrets:  string(cp.config.secrets),
 		}
 	} else {
 		return nil, fmt.Errorf("workspace or namespace identity must be set")
 	}
+	if cp.config.coalesce {
+		cp.Client = &coalescingClient{Client: cp.Client, calls: make(map[time.Time]*coalescedCall)}
+	}
 	return cp, nil
 }
+
+// GetAccountSecret returns the secret of an account, regardless of whether secrets are embedded in the workspace
+// configs or not. See [SecretCache] for fetching secrets on demand.
+// It returns [ErrUnsupportedOperation] if the client doesn't implement [AccountSecretGetter].
+func (cp *ControlPlane) GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error) {
+	return getAccountSecret(ctx, cp.Client, accountID)
+}
+
+func (cp *ControlPlane) baseClient(defaultBaseUrl string) *base.Client {
+	baseUrl := cp.config.baseUrl
+	if baseUrl == nil {
+		baseUrl, _ = url.Parse(defaultBaseUrl)
+	}
+	c := &base.Client{
+		HTTPClient:        cp.config.httpClient,
+		BaseURL:           baseUrl,
+		RateLimiter:       cp.config.rateLimiter,
+		InFlight:          cp.config.inFlight,
+		MaxResponseSize:   cp.config.maxResponseSize,
+		MaxDecodeDuration: cp.config.maxDecodeDuration,
+		CheckContentType:  cp.config.checkContentType,
+		ErrorBodyLimit:    cp.config.errorBodyLimit,
+	}
+	if len(cp.config.fallbackUrls) > 0 {
+		c.Failover = &base.Failover{
+			Fallbacks:     cp.config.fallbackUrls,
+			ProbeInterval: DefaultPrimaryProbeInterval,
+		}
+		if cp.config.probeInterval != nil {
+			c.Failover.ProbeInterval = *cp.config.probeInterval
+		}
+		if f := cp.config.onEndpointResponse; f != nil {
+			c.Failover.OnResponse = func(baseURL *url.URL, err error) { f(baseURL.String(), err) }
+		}
+	}
+	return c
+}
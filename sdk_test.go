@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -196,7 +198,7 @@ func TestIncrementalUpdates(t *testing.T) {
 	require.Equal(t, receivedUpdatedAfter[3], expectedUpdatedAfter, updatedAfterTimeFormat)
 
 	// last request, ideally the application should detect that there is an inconsistency and trigger a full update
-	// although that behaviour is not tested here
+	// (see poller.WithInconsistencyRecovery) although that behaviour is not tested here
 	wcs = &modelv2.WorkspaceConfigs{} // reset the workspace configs
 	err = cpSDK.GetWorkspaceConfigs(ctx, wcs, latestUpdatedAt)
 	require.NoError(t, err)
@@ -277,6 +279,24 @@ func TestGetNamespaceWorkspaces(t *testing.T) {
 		require.ErrorAs(t, err, &unexpectedStatusErr)
 		require.Equal(t, http.StatusInternalServerError, unexpectedStatusErr.StatusCode)
 	})
+
+	t.Run("invalid response code with secrets in the body", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"invalid credentials","secret":"service-secret"}`))
+		}))
+		defer ts.Close()
+		cpSDK, err := New(
+			WithBaseUrl(ts.URL),
+			WithNamespaceIdentity("ns", "service-secret"),
+		)
+		require.NoError(t, err)
+		_, err = cpSDK.GetNamespaceWorkspaces(context.Background())
+		var unexpectedStatusErr *UnexpectedStatusCodeError
+		require.ErrorAs(t, err, &unexpectedStatusErr)
+		require.JSONEq(t, `{"message":"invalid credentials","secret":"[REDACTED]"}`, string(unexpectedStatusErr.Body))
+		require.NotContains(t, err.Error(), "service-secret")
+	})
 }
 
 func TestSecrets(t *testing.T) {
@@ -362,3 +382,278 @@ func getLatestUpdatedAt() func(list diff.UpdateableObject[string]) (time.Time, t
 		return latestUpdatedAt, localUpdatedAt
 	}
 }
+
+func TestBaseUrlFailover(t *testing.T) {
+	const namespace = "test-namespace"
+	var primaryStatus atomic.Int64
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/configuration/v2/namespaces/"+namespace+"/workspace-ids", r.URL.Path)
+		if status := int(primaryStatus.Load()); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": ["primary"]}`))
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/prefix/configuration/v2/namespaces/"+namespace+"/workspace-ids", r.URL.Path)
+		user, _, _ := r.BasicAuth()
+		require.Equal(t, "secret", user)
+		_, _ = w.Write([]byte(`{"data": ["fallback"]}`))
+	}))
+	defer fallback.Close()
+	unavailable := httptest.NewServer(http.NotFoundHandler())
+	unavailable.Close()
+
+	newClient := func(t *testing.T, probeInterval time.Duration) (*ControlPlane, func() []string) {
+		var (
+			mu       sync.Mutex
+			attempts []string
+		)
+		cpSDK, err := New(
+			WithBaseUrls(primary.URL, unavailable.URL, fallback.URL+"/prefix"),
+			WithPrimaryProbeInterval(probeInterval),
+			WithOnEndpointResponse(func(baseUrl string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					baseUrl += " (failed)"
+				}
+				attempts = append(attempts, baseUrl)
+			}),
+			WithNamespaceIdentity(namespace, "secret"),
+		)
+		require.NoError(t, err)
+		return cpSDK, func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			defer func() { attempts = nil }()
+			return attempts
+		}
+	}
+	getWorkspaces := func(t *testing.T, cpSDK *ControlPlane) []string {
+		workspaceIDs, err := cpSDK.GetNamespaceWorkspaces(context.Background())
+		require.NoError(t, err)
+		return workspaceIDs
+	}
+
+	t.Run("fail over and stick to the fallback", func(t *testing.T) {
+		primaryStatus.Store(http.StatusServiceUnavailable)
+		cpSDK, attempts := newClient(t, time.Hour)
+
+		require.Equal(t, []string{"fallback"}, getWorkspaces(t, cpSDK))
+		require.Equal(t, []string{primary.URL + " (failed)", unavailable.URL + " (failed)", fallback.URL + "/prefix"}, attempts())
+
+		primaryStatus.Store(http.StatusOK)
+		require.Equal(t, []string{"fallback"}, getWorkspaces(t, cpSDK))
+		require.Equal(t, []string{fallback.URL + "/prefix"}, attempts())
+	})
+
+	t.Run("probe the primary", func(t *testing.T) {
+		primaryStatus.Store(http.StatusBadGateway)
+		cpSDK, attempts := newClient(t, 0)
+
+		require.Equal(t, []string{"fallback"}, getWorkspaces(t, cpSDK))
+		require.Len(t, attempts(), 3)
+		require.Equal(t, []string{"fallback"}, getWorkspaces(t, cpSDK))
+		require.Equal(t, []string{primary.URL + " (failed)", fallback.URL + "/prefix"}, attempts())
+
+		primaryStatus.Store(http.StatusOK)
+		require.Equal(t, []string{"primary"}, getWorkspaces(t, cpSDK))
+		require.Equal(t, []string{"primary"}, getWorkspaces(t, cpSDK))
+		require.Equal(t, []string{primary.URL, primary.URL}, attempts())
+	})
+
+	t.Run("no failover on client errors", func(t *testing.T) {
+		primaryStatus.Store(http.StatusUnauthorized)
+		cpSDK, attempts := newClient(t, 0)
+
+		_, err := cpSDK.GetNamespaceWorkspaces(context.Background())
+		var unexpectedStatusErr *UnexpectedStatusCodeError
+		require.ErrorAs(t, err, &unexpectedStatusErr)
+		require.Equal(t, http.StatusUnauthorized, unexpectedStatusErr.StatusCode)
+		require.Equal(t, []string{primary.URL + " (failed)"}, attempts())
+	})
+
+	t.Run("all base urls failing", func(t *testing.T) {
+		primaryStatus.Store(http.StatusInternalServerError)
+		cpSDK, err := New(
+			WithBaseUrls(primary.URL, unavailable.URL),
+			WithNamespaceIdentity(namespace, "secret"),
+		)
+		require.NoError(t, err)
+
+		_, err = cpSDK.GetNamespaceWorkspaces(context.Background())
+		require.Error(t, err)
+		require.NotErrorAs(t, err, new(*UnexpectedStatusCodeError), "the error of the last base url is returned")
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := New(WithBaseUrls(primary.URL, "://invalid"), WithNamespaceIdentity(namespace, "secret"))
+		require.ErrorContains(t, err, "invalid fallback base url")
+		_, err = New(WithPrimaryProbeInterval(-time.Second), WithNamespaceIdentity(namespace, "secret"))
+		require.EqualError(t, err, "invalid primary probe interval: -1s")
+	})
+}
+
+func TestRequestLimits(t *testing.T) {
+	t.Run("rate limit", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data": ["ws1"]}`))
+		}))
+		defer ts.Close()
+		cpSDK, err := New(
+			WithBaseUrl(ts.URL),
+			WithNamespaceIdentity("ns", "secret"),
+			WithRateLimit(20, 2),
+		)
+		require.NoError(t, err)
+
+		start := time.Now()
+		for range 4 {
+			_, err := cpSDK.GetNamespaceWorkspaces(context.Background())
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "2 requests of the burst, then 1 every 50ms")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = cpSDK.GetNamespaceWorkspaces(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		var (
+			inFlight, maxInFlight atomic.Int64
+			requests              atomic.Int64
+		)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			requests.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"data": ["ws1"]}`))
+		}))
+		defer ts.Close()
+		cpSDK, err := New(
+			WithBaseUrl(ts.URL),
+			WithNamespaceIdentity("ns", "secret"),
+			WithMaxInFlight(2),
+		)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				_, err := cpSDK.GetNamespaceWorkspaces(context.Background())
+				require.NoError(t, err)
+			})
+		}
+		wg.Wait()
+		require.EqualValues(t, 10, requests.Load())
+		require.LessOrEqual(t, maxInFlight.Load(), int64(2))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := New(WithRateLimit(0, 1), WithNamespaceIdentity("ns", "secret"))
+		require.EqualError(t, err, "invalid rate limit: 0 requests per second with a burst of 1")
+		_, err = New(WithMaxInFlight(0), WithNamespaceIdentity("ns", "secret"))
+		require.EqualError(t, err, "invalid max in flight requests: 0")
+	})
+}
+
+func TestResponseGuards(t *testing.T) {
+	const body = `{"workspaces": {"ws-1": {"updatedAt": "2024-01-01T00:00:00Z"}}}`
+	t.Run("max response size", func(t *testing.T) {
+		var chunked atomic.Bool
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if chunked.Load() {
+				w.(http.Flusher).Flush() // no content length
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+		defer ts.Close()
+
+		for _, isChunked := range []bool{false, true} {
+			chunked.Store(isChunked)
+			cpSDK, err := New(WithBaseUrl(ts.URL), WithNamespaceIdentity("ns", "secret"), WithMaxResponseSize(int64(len(body))-1))
+			require.NoError(t, err)
+			err = cpSDK.GetWorkspaceConfigs(context.Background(), &modelv2.WorkspaceConfigs{}, time.Time{})
+			require.ErrorIs(t, err, ErrResponseTooLarge, "chunked: %v", isChunked)
+
+			cpSDK, err = New(WithBaseUrl(ts.URL), WithNamespaceIdentity("ns", "secret"), WithMaxResponseSize(int64(len(body))))
+			require.NoError(t, err)
+			wcs := &modelv2.WorkspaceConfigs{}
+			require.NoError(t, cpSDK.GetWorkspaceConfigs(context.Background(), wcs, time.Time{}), "chunked: %v", isChunked)
+			require.Contains(t, wcs.Workspaces, "ws-1")
+		}
+	})
+
+	t.Run("max decode duration", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body[:10]))
+			w.(http.Flusher).Flush()
+			select { // stall in the middle of the body
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer ts.Close()
+		cpSDK, err := New(WithBaseUrl(ts.URL), WithNamespaceIdentity("ns", "secret"), WithMaxDecodeDuration(50*time.Millisecond))
+		require.NoError(t, err)
+
+		start := time.Now()
+		err = cpSDK.GetWorkspaceConfigs(context.Background(), &modelv2.WorkspaceConfigs{}, time.Time{})
+		require.ErrorIs(t, err, ErrDecodeTimeout)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("content type check", func(t *testing.T) {
+		var contentType atomic.Value
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType.Load().(string))
+			_, _ = w.Write([]byte(body))
+		}))
+		defer ts.Close()
+		cpSDK, err := New(WithBaseUrl(ts.URL), WithNamespaceIdentity("ns", "secret"), WithContentTypeCheck())
+		require.NoError(t, err)
+
+		for _, valid := range []string{"application/json", "application/json; charset=utf-8", "application/problem+json"} {
+			contentType.Store(valid)
+			require.NoError(t, cpSDK.GetWorkspaceConfigs(context.Background(), &modelv2.WorkspaceConfigs{}, time.Time{}), valid)
+		}
+		contentType.Store("text/html; charset=utf-8")
+		err = cpSDK.GetWorkspaceConfigs(context.Background(), &modelv2.WorkspaceConfigs{}, time.Time{})
+		var contentTypeErr *UnexpectedContentTypeError
+		require.ErrorAs(t, err, &contentTypeErr)
+		require.Equal(t, "text/html; charset=utf-8", contentTypeErr.ContentType)
+	})
+
+	t.Run("error body limit", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("upstream unavailable"))
+		}))
+		defer ts.Close()
+		cpSDK, err := New(WithBaseUrl(ts.URL), WithNamespaceIdentity("ns", "secret"), WithErrorBodyLimit(8))
+		require.NoError(t, err)
+
+		_, err = cpSDK.GetNamespaceWorkspaces(context.Background())
+		var unexpectedStatusErr *UnexpectedStatusCodeError
+		require.ErrorAs(t, err, &unexpectedStatusErr)
+		require.Equal(t, "upstream", string(unexpectedStatusErr.Body))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, option := range []Option{WithMaxResponseSize(0), WithMaxDecodeDuration(0), WithErrorBodyLimit(-1)} {
+			_, err := New(option, WithNamespaceIdentity("ns", "secret"))
+			require.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package cpsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// AccountSecretGetter fetches the secret of an account, e.g. [ControlPlane]. The clients of the control plane
+// implement it too, on top of [Client].
+type AccountSecretGetter interface {
+	GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error)
+}
+
+// getAccountSecret fetches the secret of an account through client, if it is an [AccountSecretGetter].
+func getAccountSecret(ctx context.Context, client Client, accountID string) (*modelv2.AccountSecret, error) {
+	getter, ok := client.(AccountSecretGetter)
+	if !ok {
+		return nil, ErrUnsupportedOperation
+	}
+	return getter.GetAccountSecret(ctx, accountID)
+}
+
+// SecretCache fetches account secrets on demand, as an alternative to embedding all of them in every workspace
+// configs response with [SecretsEmbed]. Secrets are cached in memory by account ID and SecretVersion, and evicted
+// by [SecretCache.Sync] as soon as the polled SecretVersion of their account changes.
+// Its methods are safe for concurrent use.
+type SecretCache struct {
+	getter AccountSecretGetter
+
+	mu      sync.Mutex
+	secrets map[string]*cachedSecret
+	fetches map[secretKey]*secretFetch
+}
+
+// cachedSecret is a fetched secret together with the version it was requested for, which can differ from the
+// fetched one, e.g. while the control plane catches up with a rotation.
+type cachedSecret struct {
+	*modelv2.AccountSecret
+	requested int
+}
+
+func (s *cachedSecret) matches(secretVersion int) bool {
+	return s.SecretVersion == secretVersion || s.requested == secretVersion
+}
+
+// secretKey identifies the requests for a version of the secret of an account.
+type secretKey struct {
+	accountID     string
+	secretVersion int
+}
+
+// secretFetch is a request for a version of the secret of an account, shared by the concurrent calls missing the
+// cache for it.
+type secretFetch struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int // guarded by the mutex of the cache
+
+	secret *modelv2.AccountSecret
+	err    error
+}
+
+// NewSecretCache returns an empty secret cache fetching secrets through getter.
+func NewSecretCache(getter AccountSecretGetter) *SecretCache {
+	return &SecretCache{
+		getter:  getter,
+		secrets: make(map[string]*cachedSecret),
+		fetches: make(map[secretKey]*secretFetch),
+	}
+}
+
+// Get returns the secret of an account with the given secret version, fetching it if it is not cached.
+// If the control plane returns a different version than the requested one, the returned secret is cached for both
+// versions, so that it is not fetched again on every call. Concurrent calls missing the cache for the same version
+// of the secret of an account share a single request, which is only cancelled once all of them are.
+func (c *SecretCache) Get(ctx context.Context, accountID string, secretVersion int) (map[string]any, error) {
+	key := secretKey{accountID: accountID, secretVersion: secretVersion}
+	c.mu.Lock()
+	if cached, ok := c.secrets[accountID]; ok && cached.matches(secretVersion) {
+		c.mu.Unlock()
+		return cached.Secret, nil
+	}
+	f, ok := c.fetches[key]
+	if !ok {
+		// the request outlives the context of the caller starting it, as long as other callers wait for it
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &secretFetch{done: make(chan struct{}), cancel: cancel}
+		c.fetches[key] = f
+		go c.fetch(fetchCtx, key, f)
+	}
+	f.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		return f.secret.Secret, nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if f.waiters--; f.waiters == 0 {
+			f.cancel()
+			c.forget(key, f)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// fetch fetches a version of the secret of an account and caches it, unless the fetch was forgotten in the meantime
+// by [SecretCache.Sync] or [SecretCache.Invalidate], or a later version was cached by another fetch.
+func (c *SecretCache) fetch(ctx context.Context, key secretKey, f *secretFetch) {
+	defer f.cancel()
+	secret, err := c.getter.GetAccountSecret(ctx, key.accountID)
+	if err != nil {
+		err = fmt.Errorf("getting secret of account %q: %w", key.accountID, err)
+	}
+
+	c.mu.Lock()
+	if c.fetches[key] == f && err == nil {
+		if cached, ok := c.secrets[key.accountID]; !ok || cached.SecretVersion <= secret.SecretVersion {
+			c.secrets[key.accountID] = &cachedSecret{AccountSecret: secret, requested: key.secretVersion}
+		}
+	}
+	c.forget(key, f)
+	c.mu.Unlock()
+	f.secret, f.err = secret, err
+	close(f.done)
+}
+
+// forget makes the following calls start a new fetch, unless it has already been done for this fetch.
+func (c *SecretCache) forget(key secretKey, f *secretFetch) {
+	if c.fetches[key] == f {
+		delete(c.fetches, key)
+	}
+}
+
+// Account returns the secret of an account of a workspace. Embedded secrets are returned as they are.
+func (c *SecretCache) Account(ctx context.Context, wc *modelv2.WorkspaceConfig, accountID string) (map[string]any, error) {
+	account := wc.Accounts[accountID]
+	if account == nil {
+		return nil, fmt.Errorf("account %q not found", accountID)
+	}
+	if account.Secret != nil {
+		return account.Secret, nil
+	}
+	return c.Get(ctx, accountID, account.SecretVersion)
+}
+
+// Source returns the secret of the account of a source, see [modelv2.WorkspaceConfig.SourceAccount].
+// If the secret version of the source is stale, the current secret of the account is returned anyway.
+func (c *SecretCache) Source(ctx context.Context, wc *modelv2.WorkspaceConfig, sourceID string) (map[string]any, error) {
+	if _, err := wc.SourceAccount(sourceID); err != nil {
+		var stale *modelv2.StaleSecretError
+		if !errors.As(err, &stale) {
+			return nil, err
+		}
+	}
+	return c.Account(ctx, wc, wc.Sources[sourceID].AccountID)
+}
+
+// Sync evicts the secrets of the accounts whose SecretVersion changed, or that are no longer part of wcs.
+// It is meant to be called with the cached workspace configs, after every update.
+func (c *SecretCache) Sync(wcs *modelv2.WorkspaceConfigs) {
+	versions := make(map[string]int)
+	for _, wc := range wcs.Workspaces {
+		if wc == nil {
+			continue
+		}
+		for accountID, account := range wc.Accounts {
+			if account != nil {
+				versions[accountID] = account.SecretVersion
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for accountID, secret := range c.secrets {
+		if version, ok := versions[accountID]; !ok || !secret.matches(version) {
+			delete(c.secrets, accountID)
+		}
+	}
+	// pending fetches of stale versions can complete, but their secrets are not cached
+	maps.DeleteFunc(c.fetches, func(key secretKey, _ *secretFetch) bool {
+		version, ok := versions[key.accountID]
+		return !ok || version != key.secretVersion
+	})
+}
+
+// Invalidate evicts the secret of an account, including the ones being fetched, if any.
+func (c *SecretCache) Invalidate(accountID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.secrets, accountID)
+	maps.DeleteFunc(c.fetches, func(key secretKey, _ *secretFetch) bool { return key.accountID == accountID })
+}
@@ -0,0 +1,298 @@
+package cpsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/testhelper/httptest"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+func TestSecretCache(t *testing.T) {
+	const accountID = "2gViSzlt6hyqKmPsrdkFaC1gxke"
+
+	// newServer returns a fake control plane serving the secret of the account with the version in secretVersion
+	newServer := func(t *testing.T, path string, secretVersion *int) (*httptest.Server, *int) {
+		t.Helper()
+		var (
+			mu       sync.Mutex
+			requests int
+		)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests++
+
+			user, _, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "secret", user)
+			if r.URL.Path != path {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := fmt.Fprintf(w, `{"secret":{"access_token":"token-v%[1]d"},"secretVersion":%[1]d}`, *secretVersion)
+			require.NoError(t, err)
+		}))
+		t.Cleanup(ts.Close)
+		return ts, &requests
+	}
+
+	t.Run("namespace identity", func(t *testing.T) {
+		secretVersion := 4
+		ts, requests := newServer(t, "/configuration/v2/namespaces/test-namespace/accounts/"+accountID+"/secret", &secretVersion)
+		cpSDK, err := New(
+			WithBaseUrl(ts.URL),
+			WithNamespaceIdentity("test-namespace", "secret"),
+			WithSecrets(SecretsOmit),
+		)
+		require.NoError(t, err)
+
+		wcs := &modelv2.WorkspaceConfigs{Workspaces: map[string]*modelv2.WorkspaceConfig{
+			"ws-1": {
+				Sources: map[string]*modelv2.Source{
+					"src-1": {DefinitionName: "singer-facebook-marketing", AccountID: accountID, SecretVersion: 3},
+				},
+				Accounts: map[string]*modelv2.Account{
+					accountID: {Role: "singer-facebook-marketing", Category: modelv2.AccountCategorySource, SecretVersion: 4},
+				},
+			},
+		}}
+		wc := wcs.Workspaces["ws-1"]
+		cache := NewSecretCache(cpSDK)
+
+		secret, err := cache.Account(context.Background(), wc, accountID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "token-v4"}, secret)
+		require.Equal(t, 1, *requests)
+
+		// stale source secret versions don't prevent getting the current secret of the account
+		secret, err = cache.Source(context.Background(), wc, "src-1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "token-v4"}, secret)
+		require.Equal(t, 1, *requests, "secret should be cached")
+
+		// nothing changed, the secret stays cached
+		cache.Sync(wcs)
+		_, err = cache.Get(context.Background(), accountID, 4)
+		require.NoError(t, err)
+		require.Equal(t, 1, *requests)
+
+		// the secret version changed, the secret is fetched again
+		secretVersion = 5
+		wc.Accounts[accountID].SecretVersion = 5
+		cache.Sync(wcs)
+		secret, err = cache.Account(context.Background(), wc, accountID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "token-v5"}, secret)
+		require.Equal(t, 2, *requests)
+
+		// the account was removed, the secret is evicted
+		delete(wc.Accounts, accountID)
+		cache.Sync(wcs)
+		_, err = cache.Get(context.Background(), accountID, 5)
+		require.NoError(t, err)
+		require.Equal(t, 3, *requests)
+
+		cache.Invalidate(accountID)
+		_, err = cache.Get(context.Background(), accountID, 5)
+		require.NoError(t, err)
+		require.Equal(t, 4, *requests)
+
+		_, err = cache.Account(context.Background(), wc, accountID)
+		require.EqualError(t, err, `account "`+accountID+`" not found`)
+
+		_, err = cache.Get(context.Background(), "unknown", 1)
+		var unexpectedStatusErr *UnexpectedStatusCodeError
+		require.ErrorAs(t, err, &unexpectedStatusErr)
+		require.Equal(t, http.StatusNotFound, unexpectedStatusErr.StatusCode)
+	})
+
+	t.Run("workspace identity", func(t *testing.T) {
+		secretVersion := 1
+		ts, requests := newServer(t, "/data-plane/v2/accounts/"+accountID+"/secret", &secretVersion)
+		// the coalescing client passes account secret requests through
+		cpSDK, err := New(WithBaseUrl(ts.URL), WithWorkspaceIdentity("secret"), WithRequestCoalescing())
+		require.NoError(t, err)
+
+		secret, err := NewSecretCache(cpSDK).Get(context.Background(), accountID, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "token-v1"}, secret)
+		require.Equal(t, 1, *requests)
+	})
+
+	t.Run("concurrent misses share a request", func(t *testing.T) {
+		var (
+			requests atomic.Int64
+			release  = make(chan struct{})
+		)
+		cache := NewSecretCache(accountSecretGetterFunc(func(ctx context.Context, _ string) (*modelv2.AccountSecret, error) {
+			requests.Add(1)
+			<-release
+			return &modelv2.AccountSecret{Secret: map[string]any{"access_token": "token-v2"}, SecretVersion: 2}, nil
+		}))
+
+		var wg sync.WaitGroup
+		secrets := make([]map[string]any, 5)
+		wg.Go(func() {
+			var err error
+			secrets[0], err = cache.Get(context.Background(), accountID, 1)
+			require.NoError(t, err)
+		})
+		require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+		for i := 1; i < len(secrets); i++ {
+			wg.Go(func() {
+				var err error
+				secrets[i], err = cache.Get(context.Background(), accountID, 1)
+				require.NoError(t, err)
+			})
+		}
+		close(release)
+		wg.Wait()
+		require.EqualValues(t, 1, requests.Load())
+		for _, secret := range secrets {
+			require.Equal(t, map[string]any{"access_token": "token-v2"}, secret)
+		}
+
+		// the control plane returned a different version than the requested one, both are served from the cache
+		for _, version := range []int{1, 2} {
+			_, err := cache.Get(context.Background(), accountID, version)
+			require.NoError(t, err)
+		}
+		require.EqualValues(t, 1, requests.Load())
+
+		// and kept by Sync as long as the polled version is one of them
+		cache.Sync(&modelv2.WorkspaceConfigs{Workspaces: map[string]*modelv2.WorkspaceConfig{
+			"ws-1": {Accounts: map[string]*modelv2.Account{accountID: {SecretVersion: 1}}},
+		}})
+		_, err := cache.Get(context.Background(), accountID, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("concurrent misses for different versions don't share a request", func(t *testing.T) {
+		var (
+			requests atomic.Int64
+			release  = make(chan struct{})
+		)
+		cache := NewSecretCache(accountSecretGetterFunc(func(ctx context.Context, _ string) (*modelv2.AccountSecret, error) {
+			version := int(requests.Add(1))
+			<-release
+			return &modelv2.AccountSecret{Secret: map[string]any{"access_token": fmt.Sprintf("token-v%d", version)}, SecretVersion: version}, nil
+		}))
+
+		var (
+			wg      sync.WaitGroup
+			stale   map[string]any
+			rotated map[string]any
+		)
+		wg.Go(func() {
+			var err error
+			stale, err = cache.Get(context.Background(), accountID, 1)
+			require.NoError(t, err)
+		})
+		require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+		wg.Go(func() {
+			var err error
+			rotated, err = cache.Get(context.Background(), accountID, 2)
+			require.NoError(t, err)
+		})
+		require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		require.Equal(t, map[string]any{"access_token": "token-v1"}, stale)
+		require.Equal(t, map[string]any{"access_token": "token-v2"}, rotated)
+
+		// the stale version doesn't replace the rotated one, whichever fetch completes last
+		secret, err := cache.Get(context.Background(), accountID, 2)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "token-v2"}, secret)
+		require.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("cancelled callers don't fail the others", func(t *testing.T) {
+		var (
+			requests  atomic.Int64
+			cancelled atomic.Int64
+			release   = make(chan struct{})
+		)
+		cache := NewSecretCache(accountSecretGetterFunc(func(ctx context.Context, _ string) (*modelv2.AccountSecret, error) {
+			requests.Add(1)
+			select {
+			case <-release:
+				return &modelv2.AccountSecret{Secret: map[string]any{"access_token": "token-v1"}, SecretVersion: 1}, nil
+			case <-ctx.Done():
+				cancelled.Add(1)
+				return nil, ctx.Err()
+			}
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			_, err := cache.Get(ctx, accountID, 1)
+			require.ErrorIs(t, err, context.Canceled)
+		})
+		require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+		var secret map[string]any
+		wg.Go(func() {
+			var err error
+			secret, err = cache.Get(context.Background(), accountID, 1)
+			require.NoError(t, err)
+		})
+		require.Eventually(t, func() bool {
+			cache.mu.Lock()
+			defer cache.mu.Unlock()
+			return cache.fetches[secretKey{accountID: accountID, secretVersion: 1}].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		// the first caller leaves, the request goes on for the second one
+		cancel()
+		require.Never(t, func() bool { return cancelled.Load() > 0 }, 50*time.Millisecond, time.Millisecond)
+		close(release)
+		wg.Wait()
+		require.Equal(t, map[string]any{"access_token": "token-v1"}, secret)
+		require.EqualValues(t, 1, requests.Load())
+
+		// the request is cancelled once all callers left
+		cache.Invalidate(accountID)
+		ctx, cancel = context.WithCancel(context.Background())
+		release = make(chan struct{})
+		wg.Go(func() {
+			_, err := cache.Get(ctx, accountID, 1)
+			require.ErrorIs(t, err, context.Canceled)
+		})
+		require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+		cancel()
+		wg.Wait()
+		require.Eventually(t, func() bool { return cancelled.Load() == 1 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("unsupported client", func(t *testing.T) {
+		cpSDK := &ControlPlane{Client: &scriptedClient{}}
+		_, err := NewSecretCache(cpSDK).Get(context.Background(), accountID, 1)
+		require.ErrorIs(t, err, ErrUnsupportedOperation)
+	})
+
+	t.Run("embedded secrets", func(t *testing.T) {
+		wc := &modelv2.WorkspaceConfig{Accounts: map[string]*modelv2.Account{
+			accountID: {Secret: map[string]any{"access_token": "embedded"}},
+		}}
+		secret, err := NewSecretCache(nil).Account(context.Background(), wc, accountID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"access_token": "embedded"}, secret)
+	})
+}
+
+type accountSecretGetterFunc func(ctx context.Context, accountID string) (*modelv2.AccountSecret, error)
+
+func (f accountSecretGetterFunc) GetAccountSecret(ctx context.Context, accountID string) (*modelv2.AccountSecret, error) {
+	return f(ctx, accountID)
+}
@@ -0,0 +1,397 @@
+// Package typedconfig decodes the free-form configs of modelv2 entities (e.g. Destination.Config) into Go structs.
+package typedconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+)
+
+// Mode controls how strictly configs are decoded.
+type Mode int
+
+const (
+	// Lenient ignores unknown fields and converts values between compatible types,
+	// e.g. "123" to 123 for number fields, or true to "true" for string fields.
+	Lenient Mode = iota
+	// Strict rejects unknown fields and values whose type doesn't match the one of the field.
+	Strict
+)
+
+// DecodeError is returned when a config cannot be decoded.
+type DecodeError struct {
+	// WorkspaceID is the ID of the workspace the entity belongs to, if known.
+	WorkspaceID string
+	// EntityID is the ID of the entity the config belongs to, if known.
+	EntityID string
+	// Field is the path of the field that could not be decoded, e.g. "eventMapping[0].to".
+	Field string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("decoding config")
+	if e.EntityID != "" {
+		fmt.Fprintf(&sb, " of %q", e.EntityID)
+	}
+	if e.WorkspaceID != "" {
+		fmt.Fprintf(&sb, " in workspace %q", e.WorkspaceID)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&sb, ": field %q", e.Field)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// DecodeConfig decodes a config into a value of type T, following the json tags of its fields.
+// Types implementing [json.Unmarshaler] or [encoding.TextUnmarshaler] (e.g. time.Time) decode themselves, and
+// time.Duration fields accept both numbers of nanoseconds and strings like "5s".
+// Errors are of type [*DecodeError], reporting the first invalid field in the order of the sorted keys of the config.
+func DecodeConfig[T any](config map[string]any, mode Mode) (T, error) {
+	var v T
+	if err := decodeInto(config, &v, mode); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+func decodeInto(config map[string]any, ptr any, mode Mode) error {
+	var src any // a nil map would otherwise be a non nil interface
+	if config != nil {
+		src = config
+	}
+	return decoder{mode: mode}.decode("", src, reflect.ValueOf(ptr).Elem())
+}
+
+type decoder struct {
+	mode Mode
+}
+
+func (d decoder) decode(path string, src any, dst reflect.Value) error {
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+	if dst.Kind() != reflect.Pointer && dst.CanAddr() {
+		if ok, err := d.decodeUnmarshaler(path, src, dst); ok {
+			return err
+		}
+	}
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return d.decode(path, src, dst.Elem())
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return d.errorf(path, "unsupported field type %s", dst.Type())
+		}
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	case reflect.Struct:
+		return d.decodeStruct(path, src, dst)
+	case reflect.Map:
+		return d.decodeMap(path, src, dst)
+	case reflect.Slice:
+		items, ok := src.([]any)
+		if !ok {
+			return d.typeError(path, "array", src)
+		}
+		s := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := d.decode(fmt.Sprintf("%s[%d]", path, i), item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+	case reflect.String:
+		s, err := d.toString(path, src)
+		if err != nil {
+			return err
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := d.toBool(path, src)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return d.decodeNumber(path, src, dst)
+	default:
+		return d.errorf(path, "unsupported field type %s", dst.Type())
+	}
+}
+
+// decodeUnmarshaler decodes src into the types knowing how to decode themselves, like encoding/json does: through
+// [json.Unmarshaler], or [encoding.TextUnmarshaler] for strings. Strings are also parsed as a [time.Duration].
+// It returns false if dst is none of them, leaving src to be decoded by kind.
+func (d decoder) decodeUnmarshaler(path string, src any, dst reflect.Value) (bool, error) {
+	switch u := dst.Addr().Interface().(type) {
+	case json.Unmarshaler:
+		data, err := jsonrs.Marshal(src)
+		if err != nil {
+			return true, d.errorf(path, "encoding value: %w", err)
+		}
+		if err := u.UnmarshalJSON(data); err != nil {
+			return true, d.errorf(path, "%w", err)
+		}
+		return true, nil
+	case encoding.TextUnmarshaler:
+		s, ok := src.(string)
+		if !ok {
+			return false, nil
+		}
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return true, d.errorf(path, "%w", err)
+		}
+		return true, nil
+	}
+	if s, ok := src.(string); ok && dst.Type() == durationType {
+		duration, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			if d.mode == Lenient {
+				return false, nil // it might be a number of nanoseconds
+			}
+			return true, d.errorf(path, "cannot convert %q to a duration", s)
+		}
+		dst.SetInt(int64(duration))
+		return true, nil
+	}
+	return false, nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+func (d decoder) decodeStruct(path string, src any, dst reflect.Value) error {
+	m, ok := src.(map[string]any)
+	if !ok {
+		return d.typeError(path, "object", src)
+	}
+	fields := structFields(dst.Type())
+	// keys are sorted, so that the same field is reported when several of them are invalid
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		value := m[key]
+		index, ok := fields[key]
+		if !ok && d.mode == Lenient {
+			for name, i := range fields {
+				if strings.EqualFold(name, key) {
+					index, ok = i, true
+					break
+				}
+			}
+		}
+		if !ok {
+			if d.mode == Strict {
+				return d.errorf(joinPath(path, key), "unknown field")
+			}
+			continue
+		}
+		if err := d.decode(joinPath(path, key), value, dst.FieldByIndex(index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d decoder) decodeMap(path string, src any, dst reflect.Value) error {
+	if dst.Type().Key().Kind() != reflect.String {
+		return d.errorf(path, "unsupported field type %s", dst.Type())
+	}
+	m, ok := src.(map[string]any)
+	if !ok {
+		return d.typeError(path, "object", src)
+	}
+	out := reflect.MakeMapWithSize(dst.Type(), len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		value := m[key]
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if err := d.decode(joinPath(path, key), value, elem); err != nil {
+			return err
+		}
+		out.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+	}
+	dst.Set(out)
+	return nil
+}
+
+func (d decoder) decodeNumber(path string, src any, dst reflect.Value) error {
+	var f float64
+	switch v := src.(type) {
+	case json.Number:
+		parsed, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return d.errorf(path, "invalid number %q", v)
+		}
+		f = parsed
+	case string:
+		if d.mode == Strict {
+			return d.typeError(path, "number", src)
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return d.errorf(path, "cannot convert %q to a number", v)
+		}
+		f = parsed
+	default:
+		rv := reflect.ValueOf(src)
+		switch {
+		case rv.CanInt():
+			f = float64(rv.Int())
+		case rv.CanUint():
+			f = float64(rv.Uint())
+		case rv.CanFloat():
+			f = rv.Float()
+		default:
+			return d.typeError(path, "number", src)
+		}
+	}
+
+	switch {
+	case dst.CanInt():
+		if f != math.Trunc(f) {
+			return d.errorf(path, "%v is not an integer", f)
+		}
+		if dst.OverflowInt(int64(f)) || f > math.MaxInt64 || f < math.MinInt64 {
+			return d.errorf(path, "%v overflows %s", f, dst.Type())
+		}
+		dst.SetInt(int64(f))
+	case dst.CanUint():
+		if f != math.Trunc(f) || f < 0 {
+			return d.errorf(path, "%v is not an unsigned integer", f)
+		}
+		if dst.OverflowUint(uint64(f)) || f > math.MaxUint64 {
+			return d.errorf(path, "%v overflows %s", f, dst.Type())
+		}
+		dst.SetUint(uint64(f))
+	default:
+		if dst.OverflowFloat(f) {
+			return d.errorf(path, "%v overflows %s", f, dst.Type())
+		}
+		dst.SetFloat(f)
+	}
+	return nil
+}
+
+func (d decoder) toString(path string, src any) (string, error) {
+	if s, ok := src.(string); ok {
+		return s, nil
+	}
+	if d.mode == Lenient {
+		switch v := src.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case json.Number:
+			return v.String(), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+		if rv := reflect.ValueOf(src); rv.CanInt() || rv.CanUint() || rv.CanFloat() {
+			return fmt.Sprint(src), nil
+		}
+	}
+	return "", d.typeError(path, "string", src)
+}
+
+func (d decoder) toBool(path string, src any) (bool, error) {
+	if b, ok := src.(bool); ok {
+		return b, nil
+	}
+	if s, ok := src.(string); ok && d.mode == Lenient {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return false, d.errorf(path, "cannot convert %q to a bool", s)
+		}
+		return b, nil
+	}
+	return false, d.typeError(path, "bool", src)
+}
+
+func (d decoder) typeError(path, expected string, src any) error {
+	return d.errorf(path, "expected %s, got %s", expected, jsonType(src))
+}
+
+func (d decoder) errorf(path, format string, args ...any) error {
+	return &DecodeError{Field: path, Err: fmt.Errorf(format, args...)}
+}
+
+// structFields maps the json names of the fields of a struct to their indexes. Fields of embedded structs are
+// promoted like encoding/json does, unless they are embedded through a pointer.
+func structFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for _, sf := range reflect.VisibleFields(t) {
+		if sf.Anonymous || !sf.IsExported() || throughPointer(t, sf.Index) {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if _, ok := fields[name]; !ok || len(sf.Index) < len(fields[name]) {
+			fields[name] = sf.Index
+		}
+	}
+	return fields
+}
+
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Pointer {
+			return true
+		}
+		t = f.Type
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case json.Number:
+		return "number"
+	}
+	if rv := reflect.ValueOf(v); rv.CanInt() || rv.CanUint() || rv.CanFloat() {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
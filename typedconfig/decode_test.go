@@ -0,0 +1,166 @@
+package typedconfig_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/typedconfig"
+)
+
+type mapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type config struct {
+	APIKey        string            `json:"apiKey"`
+	SyncFrequency int               `json:"syncFrequency"`
+	Ratio         float64           `json:"ratio"`
+	UseNative     bool              `json:"useNativeSDK"`
+	Mappings      []mapping         `json:"eventMapping"`
+	Headers       map[string]string `json:"headers"`
+	Extra         any               `json:"extra"`
+	Optional      *mapping          `json:"optional"`
+	Ignored       string            `json:"-"`
+}
+
+// rawConfig keeps the JSON it is decoded from as is.
+type rawConfig string
+
+func (c *rawConfig) UnmarshalJSON(data []byte) error {
+	*c = rawConfig(data)
+	return nil
+}
+
+func TestDecodeConfig(t *testing.T) {
+	t.Run("matching types", func(t *testing.T) {
+		for _, mode := range []typedconfig.Mode{typedconfig.Lenient, typedconfig.Strict} {
+			c, err := typedconfig.DecodeConfig[config](map[string]any{
+				"apiKey":        "key",
+				"syncFrequency": json.Number("180"),
+				"ratio":         0.5,
+				"useNativeSDK":  true,
+				"eventMapping":  []any{map[string]any{"from": "a", "to": "b"}},
+				"headers":       map[string]any{"x": "y"},
+				"extra":         []any{"anything"},
+				"optional":      map[string]any{"from": "c"},
+			}, mode)
+			require.NoError(t, err)
+			require.Equal(t, config{
+				APIKey:        "key",
+				SyncFrequency: 180,
+				Ratio:         0.5,
+				UseNative:     true,
+				Mappings:      []mapping{{From: "a", To: "b"}},
+				Headers:       map[string]string{"x": "y"},
+				Extra:         []any{"anything"},
+				Optional:      &mapping{From: "c"},
+			}, c)
+		}
+	})
+
+	t.Run("nil config", func(t *testing.T) {
+		c, err := typedconfig.DecodeConfig[config](nil, typedconfig.Strict)
+		require.NoError(t, err)
+		require.Equal(t, config{}, c)
+	})
+
+	t.Run("lenient conversions", func(t *testing.T) {
+		c, err := typedconfig.DecodeConfig[config](map[string]any{
+			"apiKey":        123.0,
+			"syncFrequency": "180",
+			"useNativeSDK":  "true",
+			"unknown":       "ignored",
+			"APIKEY2":       "ignored too",
+			"EventMapping":  []any{map[string]any{"to": false}},
+		}, typedconfig.Lenient)
+		require.NoError(t, err)
+		require.Equal(t, config{
+			APIKey:        "123",
+			SyncFrequency: 180,
+			UseNative:     true,
+			Mappings:      []mapping{{To: "false"}},
+		}, c)
+	})
+
+	t.Run("unmarshalers", func(t *testing.T) {
+		type timed struct {
+			Since    time.Time     `json:"since"`
+			Timeout  time.Duration `json:"timeout"`
+			Interval time.Duration `json:"interval"`
+			Level    slog.Level    `json:"level"`
+			Raw      rawConfig     `json:"raw"`
+		}
+		for _, mode := range []typedconfig.Mode{typedconfig.Lenient, typedconfig.Strict} {
+			c, err := typedconfig.DecodeConfig[timed](map[string]any{
+				"since":    "2024-01-02T03:04:05Z",
+				"timeout":  "5s",
+				"interval": json.Number("1000"),
+				"level":    "WARN",
+				"raw":      map[string]any{"a": []any{1.0}},
+			}, mode)
+			require.NoError(t, err)
+			require.Equal(t, timed{
+				Since:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Timeout:  5 * time.Second,
+				Interval: time.Microsecond,
+				Level:    slog.LevelWarn,
+				Raw:      rawConfig(`{"a":[1]}`),
+			}, c)
+		}
+
+		c, err := typedconfig.DecodeConfig[timed](map[string]any{"interval": "1000"}, typedconfig.Lenient)
+		require.NoError(t, err)
+		require.Equal(t, time.Microsecond, c.Interval)
+
+		_, err = typedconfig.DecodeConfig[timed](map[string]any{"since": "yesterday"}, typedconfig.Lenient)
+		var de *typedconfig.DecodeError
+		require.ErrorAs(t, err, &de)
+		require.Equal(t, "since", de.Field)
+		var parseErr *time.ParseError
+		require.ErrorAs(t, err, &parseErr)
+
+		_, err = typedconfig.DecodeConfig[timed](map[string]any{"timeout": "often"}, typedconfig.Strict)
+		require.EqualError(t, err, `decoding config: field "timeout": cannot convert "often" to a duration`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			config map[string]any
+			mode   typedconfig.Mode
+			field  string
+			err    string
+		}{
+			{"unknown field", map[string]any{"unknown": 1}, typedconfig.Strict, "unknown", `decoding config: field "unknown": unknown field`},
+			{"string as number", map[string]any{"syncFrequency": "180"}, typedconfig.Strict, "syncFrequency", `decoding config: field "syncFrequency": expected number, got string`},
+			{"invalid number", map[string]any{"syncFrequency": "often"}, typedconfig.Lenient, "syncFrequency", `decoding config: field "syncFrequency": cannot convert "often" to a number`},
+			{"fractional integer", map[string]any{"syncFrequency": 1.5}, typedconfig.Lenient, "syncFrequency", `decoding config: field "syncFrequency": 1.5 is not an integer`},
+			{"number as string", map[string]any{"apiKey": 1.0}, typedconfig.Strict, "apiKey", `decoding config: field "apiKey": expected string, got number`},
+			{"nested", map[string]any{"eventMapping": []any{map[string]any{}, map[string]any{"to": true}}}, typedconfig.Strict, "eventMapping[1].to", `decoding config: field "eventMapping[1].to": expected string, got bool`},
+			{"object as array", map[string]any{"eventMapping": map[string]any{}}, typedconfig.Lenient, "eventMapping", `decoding config: field "eventMapping": expected array, got object`},
+			{"first unknown field", map[string]any{"zzz": 1, "unknown": 1, "bbb": 1, "apiKey": "key", "aaa": 1}, typedconfig.Strict, "aaa", `decoding config: field "aaa": unknown field`},
+			{"first invalid value", map[string]any{"headers": map[string]any{"y": 1, "x": 1}, "apiKey": 1.0}, typedconfig.Strict, "apiKey", `decoding config: field "apiKey": expected string, got number`},
+			{"first invalid map value", map[string]any{"headers": map[string]any{"y": 1.0, "x": 1.0}}, typedconfig.Strict, "headers.x", `decoding config: field "headers.x": expected string, got number`},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				// fields are reported in a stable order, whatever the order in which the map is iterated
+				for range 20 {
+					_, err := typedconfig.DecodeConfig[config](tc.config, tc.mode)
+					var de *typedconfig.DecodeError
+					require.ErrorAs(t, err, &de)
+					require.Equal(t, tc.field, de.Field)
+				}
+				_, err := typedconfig.DecodeConfig[config](tc.config, tc.mode)
+				var de *typedconfig.DecodeError
+				require.ErrorAs(t, err, &de)
+				require.Equal(t, tc.field, de.Field)
+				require.EqualError(t, err, tc.err)
+			})
+		}
+	})
+}
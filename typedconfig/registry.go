@@ -0,0 +1,199 @@
+package typedconfig
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// ErrNotRegistered is returned when decoding the config of an entity whose definition has no registered type.
+var ErrNotRegistered = errors.New("no config type registered")
+
+// Kind is the kind of entity a config type is registered for.
+type Kind string
+
+const (
+	// KindDestination configs are looked up by Destination.DefinitionName.
+	KindDestination Kind = "destination"
+	// KindSource configs are looked up by Source.DefinitionName.
+	KindSource Kind = "source"
+	// KindResource configs are looked up by Resource.Role.
+	KindResource Kind = "resource"
+	// KindBucket configs are looked up by Bucket.Type.
+	KindBucket Kind = "bucket"
+)
+
+type registration struct {
+	typ    reflect.Type
+	decode func(config map[string]any, mode Mode) (any, error)
+}
+
+// cacheKey identifies a revision of the config of a destination.
+type cacheKey struct {
+	destinationID string
+	revisionID    string
+}
+
+// Registry maps entity definitions to the Go types their configs are decoded into.
+// Decoded destination configs are cached per destination and RevisionID, so that a config is decoded only once
+// for as long as its revision doesn't change. Revisions that are no longer part of the workspace configs are evicted
+// by [Registry.Sync].
+// Its methods are safe for concurrent use.
+type Registry struct {
+	mode Mode
+
+	mu    sync.RWMutex
+	types map[Kind]map[string]registration
+	cache map[cacheKey]any
+}
+
+// NewRegistry returns an empty registry decoding configs with the given mode.
+func NewRegistry(mode Mode) *Registry {
+	return &Registry{
+		mode:  mode,
+		types: make(map[Kind]map[string]registration),
+		cache: make(map[cacheKey]any),
+	}
+}
+
+// Register registers T as the config type of the entities of the given kind and definition name.
+// Configs are then decoded into values of type *T. Registering a definition more than once is an error.
+func Register[T any](r *Registry, kind Kind, definitionName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.types[kind][definitionName]; ok {
+		return fmt.Errorf("config type of %s %q already registered", kind, definitionName)
+	}
+	if r.types[kind] == nil {
+		r.types[kind] = make(map[string]registration)
+	}
+	r.types[kind][definitionName] = registration{
+		typ: reflect.TypeFor[T](),
+		decode: func(config map[string]any, mode Mode) (any, error) {
+			v := new(T)
+			if err := decodeInto(config, v, mode); err != nil {
+				return nil, err
+			}
+			return v, nil
+		},
+	}
+	return nil
+}
+
+// Type returns the type registered for the given kind and definition name.
+func (r *Registry) Type(kind Kind, definitionName string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reg, ok := r.types[kind][definitionName]
+	return reg.typ, ok
+}
+
+// Decode decodes a config into the type registered for the given kind and definition name, returning a pointer to it.
+// The workspace and entity IDs are only used to describe errors, which are of type [*DecodeError].
+func (r *Registry) Decode(kind Kind, definitionName, workspaceID, entityID string, config map[string]any) (any, error) {
+	r.mu.RLock()
+	reg, ok := r.types[kind][definitionName]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &DecodeError{
+			WorkspaceID: workspaceID,
+			EntityID:    entityID,
+			Err:         fmt.Errorf("%w for %s %q", ErrNotRegistered, kind, definitionName),
+		}
+	}
+	v, err := reg.decode(config, r.mode)
+	if err != nil {
+		var de *DecodeError
+		if errors.As(err, &de) {
+			de.WorkspaceID, de.EntityID = workspaceID, entityID
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// Destination decodes the config of a destination. The result is cached until the RevisionID of the destination
+// changes, thus it must not be modified. Destinations without a RevisionID are decoded every time.
+func (r *Registry) Destination(workspaceID, destinationID string, d *modelv2.Destination) (any, error) {
+	key := cacheKey{destinationID: destinationID, revisionID: d.RevisionID}
+	if d.RevisionID != "" {
+		r.mu.RLock()
+		v, ok := r.cache[key]
+		r.mu.RUnlock()
+		if ok {
+			return v, nil
+		}
+	}
+	v, err := r.Decode(KindDestination, d.DefinitionName, workspaceID, destinationID, d.Config)
+	if err != nil {
+		return nil, err
+	}
+	if d.RevisionID != "" {
+		r.mu.Lock()
+		r.cache[key] = v
+		r.mu.Unlock()
+	}
+	return v, nil
+}
+
+// Source decodes the config of a source.
+func (r *Registry) Source(workspaceID, sourceID string, s *modelv2.Source) (any, error) {
+	return r.Decode(KindSource, s.DefinitionName, workspaceID, sourceID, s.Config)
+}
+
+// Resource decodes the config of a resource, using its role as definition name.
+func (r *Registry) Resource(workspaceID, resourceID string, res *modelv2.Resource) (any, error) {
+	return r.Decode(KindResource, res.Role, workspaceID, resourceID, res.Config)
+}
+
+// Bucket decodes the config of the storage bucket of a workspace, using its type as definition name.
+func (r *Registry) Bucket(workspaceID string, b *modelv2.Bucket) (any, error) {
+	return r.Decode(KindBucket, string(b.Type), workspaceID, "", b.Config)
+}
+
+// Evict removes the cached configs of a destination, e.g. after it has been deleted.
+func (r *Registry) Evict(destinationID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	maps.DeleteFunc(r.cache, func(key cacheKey, _ any) bool { return key.destinationID == destinationID })
+}
+
+// Sync evicts the cached configs of the destinations that are no longer part of wcs, or whose RevisionID changed.
+// It is meant to be called with the cached workspace configs, after every update.
+func (r *Registry) Sync(wcs *modelv2.WorkspaceConfigs) {
+	current := make(map[cacheKey]struct{})
+	for _, wc := range wcs.Workspaces {
+		if wc == nil {
+			continue
+		}
+		for destinationID, d := range wc.Destinations {
+			if d != nil {
+				current[cacheKey{destinationID: destinationID, revisionID: d.RevisionID}] = struct{}{}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	maps.DeleteFunc(r.cache, func(key cacheKey, _ any) bool {
+		_, ok := current[key]
+		return !ok
+	})
+}
+
+// As converts a value returned by the registry to the type it was registered with.
+func As[T any](v any, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	t, ok := v.(*T)
+	if !ok {
+		return nil, fmt.Errorf("config decoded as %T, not %s", v, reflect.TypeFor[*T]())
+	}
+	return t, nil
+}
@@ -0,0 +1,145 @@
+package typedconfig_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/typedconfig"
+)
+
+type bigQueryConfig struct {
+	Project        string            `json:"project"`
+	Location       string            `json:"location"`
+	BucketName     string            `json:"bucketName"`
+	SyncFrequency  int               `json:"syncFrequency"`
+	SkipUsersTable bool              `json:"skipUsersTable"`
+	ConnectionMode map[string]string `json:"connectionMode"`
+}
+
+func TestRegistry(t *testing.T) {
+	data, err := os.ReadFile("../testdata/sample_namespace.json")
+	require.NoError(t, err)
+	wcs := &modelv2.WorkspaceConfigs{}
+	require.NoError(t, jsonrs.Unmarshal(data, wcs))
+
+	const (
+		workspaceID   = "2hCBi02C8xYS8Rsy1m9bJjTlKy6"
+		destinationID = "2hQdZuO7jPzgcw1uN9NaU1u2wPg"
+	)
+	destination := wcs.Workspaces[workspaceID].Destinations[destinationID]
+
+	t.Run("decode destination", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Lenient)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindDestination, "BQ"))
+
+		c, err := typedconfig.As[bigQueryConfig](r.Destination(workspaceID, destinationID, destination))
+		require.NoError(t, err)
+		require.Equal(t, &bigQueryConfig{
+			Project:        "a-very-cool-project",
+			Location:       "US",
+			BucketName:     "rudderstack_bucket_name_1",
+			SyncFrequency:  180,
+			ConnectionMode: map[string]string{"web": "cloud"},
+		}, c)
+
+		_, err = typedconfig.As[config](r.Destination(workspaceID, destinationID, destination))
+		require.EqualError(t, err, "config decoded as *typedconfig_test.bigQueryConfig, not *typedconfig_test.config")
+	})
+
+	t.Run("strict errors name workspace, entity and field", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Strict)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindDestination, "BQ"))
+
+		_, err := r.Destination(workspaceID, destinationID, destination)
+		var de *typedconfig.DecodeError
+		require.ErrorAs(t, err, &de)
+		require.Equal(t, workspaceID, de.WorkspaceID)
+		require.Equal(t, destinationID, de.EntityID)
+		require.NotEmpty(t, de.Field)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Lenient)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindSource, "BQ"))
+
+		_, err := r.Destination(workspaceID, destinationID, destination)
+		require.ErrorIs(t, err, typedconfig.ErrNotRegistered)
+		require.EqualError(t, err, `decoding config of "2hQdZuO7jPzgcw1uN9NaU1u2wPg" in workspace "2hCBi02C8xYS8Rsy1m9bJjTlKy6": no config type registered for destination "BQ"`)
+	})
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Lenient)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindDestination, "BQ"))
+		require.EqualError(t, typedconfig.Register[config](r, typedconfig.KindDestination, "BQ"), `config type of destination "BQ" already registered`)
+		typ, ok := r.Type(typedconfig.KindDestination, "BQ")
+		require.True(t, ok)
+		require.Equal(t, "bigQueryConfig", typ.Name())
+	})
+
+	t.Run("cached per revision", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Lenient)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindDestination, "BQ"))
+
+		d := *destination
+		first, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		second, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		d.Config = map[string]any{"project": "another-project"}
+		d.RevisionID = "new-revision"
+		third, err := typedconfig.As[bigQueryConfig](r.Destination(workspaceID, destinationID, &d))
+		require.NoError(t, err)
+		require.Equal(t, "another-project", third.Project)
+
+		r.Evict(destinationID)
+		fourth, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		require.NotSame(t, third, fourth)
+
+		// the revisions that are no longer part of the workspace configs are evicted
+		r.Sync(&modelv2.WorkspaceConfigs{Workspaces: map[string]*modelv2.WorkspaceConfig{
+			workspaceID: {Destinations: map[string]*modelv2.Destination{destinationID: &d}},
+		}})
+		synced, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		require.Same(t, fourth, synced)
+		previous, err := r.Destination(workspaceID, destinationID, destination)
+		require.NoError(t, err)
+		require.NotSame(t, first, previous)
+		r.Sync(&modelv2.WorkspaceConfigs{})
+		synced, err = r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		require.NotSame(t, fourth, synced)
+
+		d.RevisionID = ""
+		fifth, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		sixth, err := r.Destination(workspaceID, destinationID, &d)
+		require.NoError(t, err)
+		require.NotSame(t, fifth, sixth)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		r := typedconfig.NewRegistry(typedconfig.Lenient)
+		require.NoError(t, typedconfig.Register[bigQueryConfig](r, typedconfig.KindDestination, "BQ"))
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := r.Destination(workspaceID, destinationID, destination)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// ValidateReferences checks the referential integrity of a workspace, i.e. that the entities referenced by its
+// entities exist:
+//   - the sources and destinations of connections
+//   - the transformations of destinations
+//   - the accounts of sources, WHT projects, SQL model versions and audience sources, with the expected category
+//     and role, see [modelv2.WorkspaceConfig.CheckAccounts]
+//   - the tracking plans of sources, see [modelv2.WorkspaceConfig.SourceTrackingPlan]
+//   - the resources of WHT projects, see [modelv2.WorkspaceConfig.WHTProjectResources]
+//   - the sources and SQL model versions of the dependency graph, see [modelv2.WorkspaceConfig.DependencyGraph]
+//
+// References to missing entities are errors, while references to deleted entities, and accounts with an unexpected
+// role or a stale secret, are warnings. Deleted entities are not checked. The Field of the findings is the JSON name
+// of the field of the entity holding the reference, e.g. "transformationIds", rather than a path in its config.
+// Only the entities with findings are reported. [Validate] includes these checks for all the workspaces.
+func ValidateReferences(workspaceID string, wc *modelv2.WorkspaceConfig) *Report {
+	c := &referenceChecker{workspaceID: workspaceID, entities: make(map[entityKey]*EntityReport)}
+	c.connections(wc)
+	c.destinations(wc)
+	c.sources(wc)
+	c.accounts(wc)
+	c.whtProjects(wc)
+	c.dependencies(wc)
+
+	r := &Report{}
+	for _, e := range c.entities {
+		slices.SortFunc(e.Findings, compareFindings)
+		r.Entities = append(r.Entities, *e)
+	}
+	sortEntities(r.Entities)
+	return r
+}
+
+type entityKey struct {
+	entityType EntityType
+	entityID   string
+}
+
+type referenceChecker struct {
+	workspaceID string
+	entities    map[entityKey]*EntityReport
+}
+
+func (c *referenceChecker) add(severity Severity, entityType EntityType, entityID, field, format string, args ...any) {
+	key := entityKey{entityType: entityType, entityID: entityID}
+	e, ok := c.entities[key]
+	if !ok {
+		e = &EntityReport{WorkspaceID: c.workspaceID, EntityType: entityType, EntityID: entityID}
+		c.entities[key] = e
+	}
+	e.Findings = append(e.Findings, Finding{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *referenceChecker) connections(wc *modelv2.WorkspaceConfig) {
+	for connectionID, conn := range wc.Connections {
+		if conn == nil {
+			continue
+		}
+		if source, ok := wc.Sources[conn.SourceID]; !ok || source == nil {
+			c.add(SeverityError, EntityTypeConnection, connectionID, "sourceId", "source %q not found", conn.SourceID)
+		} else if source.Deleted {
+			c.add(SeverityWarning, EntityTypeConnection, connectionID, "sourceId", "source %q is deleted", conn.SourceID)
+		}
+		if destination, ok := wc.Destinations[conn.DestinationID]; !ok || destination == nil {
+			c.add(SeverityError, EntityTypeConnection, connectionID, "destinationId", "destination %q not found", conn.DestinationID)
+		} else if destination.Deleted {
+			c.add(SeverityWarning, EntityTypeConnection, connectionID, "destinationId", "destination %q is deleted", conn.DestinationID)
+		}
+	}
+}
+
+func (c *referenceChecker) destinations(wc *modelv2.WorkspaceConfig) {
+	for destinationID, destination := range wc.Destinations {
+		if destination == nil || destination.Deleted {
+			continue
+		}
+		for _, transformationID := range destination.TransformationIDs {
+			if wc.Transformations[transformationID] == nil {
+				c.add(SeverityError, EntityTypeDestination, destinationID, "transformationIds", "transformation %q not found", transformationID)
+			}
+		}
+	}
+}
+
+func (c *referenceChecker) sources(wc *modelv2.WorkspaceConfig) {
+	for sourceID, source := range wc.Sources {
+		if source == nil || source.Deleted {
+			continue
+		}
+		_, err := wc.SourceTrackingPlan(sourceID)
+		var deletedErr *modelv2.DeletedTrackingPlanError
+		switch {
+		case err == nil, errors.Is(err, modelv2.ErrNoTrackingPlan):
+		case errors.As(err, &deletedErr):
+			c.add(SeverityWarning, EntityTypeSource, sourceID, "dgSourceTrackingPlanConfig", "%v", err)
+		default:
+			c.add(SeverityError, EntityTypeSource, sourceID, "dgSourceTrackingPlanConfig", "%v", err)
+		}
+	}
+}
+
+func (c *referenceChecker) accounts(wc *modelv2.WorkspaceConfig) {
+	for ref := range wc.AccountReferences() {
+		_, err := wc.ResolveAccount(ref)
+		var (
+			roleErr  *modelv2.AccountRoleError
+			staleErr *modelv2.StaleSecretError
+		)
+		switch {
+		case err == nil:
+		case errors.As(err, &roleErr), errors.As(err, &staleErr):
+			c.add(SeverityWarning, EntityType(ref.EntityType), ref.EntityID, ref.Field, "%v", err)
+		default:
+			c.add(SeverityError, EntityType(ref.EntityType), ref.EntityID, ref.Field, "%v", err)
+		}
+	}
+}
+
+func (c *referenceChecker) whtProjects(wc *modelv2.WorkspaceConfig) {
+	for projectID, project := range wc.WHTProjects {
+		if project == nil || project.Deleted {
+			continue
+		}
+		var missingErr *modelv2.MissingResourcesError
+		if _, err := wc.WHTProjectResources(projectID); errors.As(err, &missingErr) {
+			for _, resourceID := range missingErr.ResourceIDs {
+				c.add(SeverityError, EntityTypeWHTProject, projectID, "config.resources", "resource %q not found", resourceID)
+			}
+		}
+	}
+}
+
+// dependencies reports the dangling references to sources and SQL model versions, those to accounts being already
+// reported by accounts.
+func (c *referenceChecker) dependencies(wc *modelv2.WorkspaceConfig) {
+	for _, ref := range wc.DependencyGraph().DanglingReferences() {
+		switch {
+		case ref.ReferencedType == "account":
+		case ref.Deleted:
+			c.add(SeverityWarning, EntityType(ref.EntityType), ref.EntityID, ref.Field, "%s %q is deleted", ref.ReferencedType, ref.ReferencedID)
+		default:
+			c.add(SeverityError, EntityType(ref.EntityType), ref.EntityID, ref.Field, "%s %q not found", ref.ReferencedType, ref.ReferencedID)
+		}
+	}
+}
+
+func compareFindings(a, b Finding) int {
+	return cmp.Or(strings.Compare(a.Field, b.Field), strings.Compare(a.Message, b.Message))
+}
+
+func sortEntities(entities []EntityReport) {
+	slices.SortFunc(entities, func(a, b EntityReport) int {
+		return cmp.Or(
+			strings.Compare(a.WorkspaceID, b.WorkspaceID),
+			strings.Compare(string(a.EntityType), string(b.EntityType)),
+			strings.Compare(a.EntityID, b.EntityID),
+		)
+	})
+}
@@ -0,0 +1,107 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/validation"
+)
+
+func TestValidateReferences(t *testing.T) {
+	wc := &modelv2.WorkspaceConfig{
+		Sources: map[string]*modelv2.Source{
+			"source-1": {DefinitionName: "Javascript", AccountID: "account-1", TrackingPlanConfig: &modelv2.DGSourceTrackingPlan{TrackingPlanID: "tp-1", Version: 1}},
+			"source-2": {DefinitionName: "Android", Deleted: true, AccountID: "account-404"},
+			"source-3": {DefinitionName: "Unknown", TrackingPlanConfig: &modelv2.DGSourceTrackingPlan{TrackingPlanID: "tp-2", Version: 1}},
+			"source-4": {DefinitionName: "Javascript", TrackingPlanConfig: &modelv2.DGSourceTrackingPlan{TrackingPlanID: "tp-1", Version: 1, Deleted: true}},
+		},
+		Destinations: map[string]*modelv2.Destination{
+			"destination-1": {DefinitionName: "WEBHOOK", TransformationIDs: []string{"transformation-1", "transformation-2"}},
+			"destination-2": {DefinitionName: "WEBHOOK", Deleted: true, TransformationIDs: []string{"transformation-3"}},
+		},
+		Connections: map[string]*modelv2.Connection{
+			"connection-1": {SourceID: "source-1", DestinationID: "destination-1"},
+			"connection-2": {SourceID: "source-2", DestinationID: "destination-3"},
+		},
+		Transformations: map[string]*modelv2.Transformation{"transformation-1": {}},
+		TrackingPlans:   map[string]*modelv2.TrackingPlan{"tp-1": {Version: 1}},
+		Accounts: map[string]*modelv2.Account{
+			"account-1": {Category: modelv2.AccountCategoryDestination},
+			"account-2": {Category: modelv2.AccountCategoryWHT},
+		},
+		WHTProjects: map[string]*modelv2.WHTProject{
+			"project-1": {GitRepoAccountID: "account-2", Config: modelv2.WHTProjectConfig{Resources: []string{"resource-1"}}},
+		},
+		SQLModelVersionSourceConnections: []*modelv2.SQLModelVersionSourceConnection{
+			{SourceID: "source-1", SQLModelVersionID: "version-1"},
+		},
+		AudienceSources: []*modelv2.AudienceSource{
+			{ID: "audience-1", SourceID: "source-2", AccountID: "account-3"},
+		},
+	}
+
+	expected := []validation.EntityReport{
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeAudienceSource, EntityID: "audience-1", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "accountId", Message: `account "account-3" referenced by audienceSource "audience-1" (accountId) not found`},
+			{Severity: validation.SeverityWarning, Field: "sourceId", Message: `source "source-2" is deleted`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeConnection, EntityID: "connection-2", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "destinationId", Message: `destination "destination-3" not found`},
+			{Severity: validation.SeverityWarning, Field: "sourceId", Message: `source "source-2" is deleted`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeDestination, EntityID: "destination-1", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "transformationIds", Message: `transformation "transformation-2" not found`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeSource, EntityID: "source-1", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "accountId", Message: `account "account-1" referenced by source "source-1" (accountId) has category "destination" instead of "source"`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeSource, EntityID: "source-3", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "dgSourceTrackingPlanConfig", Message: `tracking plan "tp-2" of source "source-3" not found`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeSource, EntityID: "source-4", Findings: []validation.Finding{
+			{Severity: validation.SeverityWarning, Field: "dgSourceTrackingPlanConfig", Message: `tracking plan "tp-1" of source "source-4" is deleted`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeSQLModelVersionSourceConnection, EntityID: "0", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "sqlModelVersionId", Message: `sqlModelVersion "version-1" not found`},
+		}},
+		{WorkspaceID: "workspace-1", EntityType: validation.EntityTypeWHTProject, EntityID: "project-1", Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Field: "config.resources", Message: `resource "resource-1" not found`},
+		}},
+	}
+
+	t.Run("workspace", func(t *testing.T) {
+		r := validation.ValidateReferences("workspace-1", wc)
+		require.Equal(t, expected, r.Entities)
+		require.True(t, r.HasErrors())
+	})
+
+	t.Run("single report with the config findings", func(t *testing.T) {
+		wcs := &modelv2.WorkspaceConfigs{
+			Workspaces:             modelv2.Workspaces{"workspace-1": wc, "workspace-2": nil},
+			SourceDefinitions:      modelv2.SourceDefinitions{"Javascript": {}},
+			DestinationDefinitions: modelv2.DestinationDefinitions{"WEBHOOK": {}},
+		}
+		r := validation.Validate(wcs)
+		require.Len(t, r.Entities, len(expected), "entities are reported once, with both kinds of findings")
+		source3 := r.Entities[4]
+		require.Equal(t, "source-3", source3.EntityID)
+		require.Equal(t, "Unknown", source3.DefinitionName)
+		require.Equal(t, []validation.Finding{
+			{Severity: validation.SeverityWarning, Message: `source definition "Unknown" not found, config not validated`},
+			expected[4].Findings[0],
+		}, source3.Findings)
+		require.Equal(t, expected[0], r.Entities[0])
+	})
+
+	t.Run("valid workspace", func(t *testing.T) {
+		r := validation.ValidateReferences("", &modelv2.WorkspaceConfig{
+			Sources:      map[string]*modelv2.Source{"source-1": {}},
+			Destinations: map[string]*modelv2.Destination{"destination-1": {}},
+			Connections:  map[string]*modelv2.Connection{"connection-1": {SourceID: "source-1", DestinationID: "destination-1"}},
+		})
+		require.Empty(t, r.Entities)
+		require.False(t, r.HasErrors())
+	})
+}
@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateSchema validates a value against the supported subset of JSON schema.
+func validateSchema(path string, schema map[string]any, value any) []Finding {
+	var findings []Finding
+	errorf := func(field, format string, args ...any) {
+		findings = append(findings, Finding{Severity: SeverityError, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return hasType(value, t) }) {
+		errorf(path, "expected %s, got %s", strings.Join(types, " or "), jsonType(value))
+		return findings // the other keywords would only report the same problem in different ways
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return equal(e, value) }) {
+		errorf(path, "value %s is not one of %s", format(value), formatList(enum))
+	}
+	if c, ok := schema["const"]; ok && !equal(c, value) {
+		errorf(path, "value %s is not %s", format(value), format(c))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range stringList(schema["required"]) {
+			if _, ok := v[key]; !ok {
+				errorf(joinPath(path, key), "required field is missing")
+			}
+		}
+		for key, fieldValue := range v {
+			if fieldSchema, ok := properties[key].(map[string]any); ok {
+				findings = append(findings, validateSchema(joinPath(path, key), fieldSchema, fieldValue)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errorf(joinPath(path, key), "unknown field")
+				}
+			case map[string]any:
+				findings = append(findings, validateSchema(joinPath(path, key), additional, fieldValue)...)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				findings = append(findings, validateSchema(fmt.Sprintf("%s[%d]", path, i), items, item)...)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if minLength, ok := number(schema["minLength"]); ok && float64(length) < minLength {
+			errorf(path, "length %d is less than %v", length, minLength)
+		}
+		if maxLength, ok := number(schema["maxLength"]); ok && float64(length) > maxLength {
+			errorf(path, "length %d is greater than %v", length, maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				errorf(path, "invalid pattern %q in schema: %v", pattern, err)
+			} else if !re.MatchString(v) {
+				errorf(path, "value %q does not match pattern %q", v, pattern)
+			}
+		}
+	default:
+		if n, ok := number(value); ok {
+			if minimum, ok := number(schema["minimum"]); ok && n < minimum {
+				errorf(path, "value %v is less than %v", n, minimum)
+			}
+			if maximum, ok := number(schema["maximum"]); ok && n > maximum {
+				errorf(path, "value %v is greater than %v", n, maximum)
+			}
+		}
+	}
+
+	for _, sub := range schemaList(schema["allOf"]) {
+		findings = append(findings, validateSchema(path, sub, value)...)
+	}
+	if anyOf := schemaList(schema["anyOf"]); len(anyOf) > 0 {
+		if !slices.ContainsFunc(anyOf, func(sub map[string]any) bool { return len(validateSchema(path, sub, value)) == 0 }) {
+			errorf(path, "value does not match any of the allowed schemas")
+		}
+	}
+	if oneOf := schemaList(schema["oneOf"]); len(oneOf) > 0 {
+		matches := 0
+		for _, sub := range oneOf {
+			if len(validateSchema(path, sub, value)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			errorf(path, "value matches %d of the allowed schemas instead of exactly one", matches)
+		}
+	}
+	if not, ok := schema["not"].(map[string]any); ok && len(validateSchema(path, not, value)) == 0 {
+		errorf(path, "value matches a disallowed schema")
+	}
+	if cond, ok := schema["if"].(map[string]any); ok {
+		branch := "else"
+		if len(validateSchema(path, cond, value)) == 0 {
+			branch = "then"
+		}
+		if sub, ok := schema[branch].(map[string]any); ok {
+			findings = append(findings, validateSchema(path, sub, value)...)
+		}
+	}
+	return findings
+}
+
+func schemaTypes(v any) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return stringList(v)
+}
+
+func schemaList(v any) []map[string]any {
+	items, _ := v.([]any)
+	list := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+func hasType(v any, typ string) bool {
+	switch typ {
+	case "integer":
+		n, ok := number(v)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := number(v)
+		return ok
+	default:
+		return jsonType(v) == typ
+	}
+}
+
+// number returns the value of a JSON number, which is either a float64 or a json.Number depending on how it was
+// decoded.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func equal(a, b any) bool {
+	if na, ok := number(a); ok {
+		nb, ok := number(b)
+		return ok && na == nb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	if _, ok := number(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func format(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+func formatList(values []any) string {
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		formatted = append(formatted, format(v))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
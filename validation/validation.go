@@ -0,0 +1,271 @@
+// Package validation checks the configs of sources and destinations against the configs of their definitions, i.e.
+// modelv2.SourceDefinition.Config and modelv2.DestinationDefinition.Config, and the references between the entities
+// of workspaces, see [ValidateReferences].
+//
+// The following keys of a definition config are interpreted:
+//   - configSchema: a JSON schema the entity config must conform to. The type, enum, const, required, properties,
+//     additionalProperties, items, minLength, maxLength, pattern, minimum, maximum, allOf, anyOf, oneOf, not and
+//     if/then/else keywords are supported, other keywords are ignored.
+//   - requiredKeys: fields that must be set to a non empty value.
+//   - secretKeys: fields that, when set, must be strings.
+//   - supportedConnectionModes: the allowed values of connectionMode, per source type.
+//   - destConfig: the fields known to the definition, per source type. Unknown fields are reported as warnings.
+//
+// The integrity checks of workspace configs live here rather than in modelv2, next to the checks of the configs
+// they share their findings with, since validating a config needs the definitions of the whole
+// [modelv2.WorkspaceConfigs]:
+//   - [ValidateReferences] checks the references of a single [modelv2.WorkspaceConfig], e.g. within tests.
+//   - [Validate] checks all the workspaces of [modelv2.WorkspaceConfigs], i.e. of a namespace, including the
+//     references to definitions, and is what cmd/cpsdk-validate runs in CI.
+//
+// Both return a [Report] listing the findings of every entity with their [Severity].
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	// SeverityError is used for configs that don't conform to their definition, and references to missing entities.
+	SeverityError Severity = "error"
+	// SeverityWarning is used for configs that conform to their definition but look suspicious, e.g. unknown fields,
+	// and references that resolve but look suspicious, e.g. to deleted entities.
+	SeverityWarning Severity = "warning"
+)
+
+// EntityType is the type of the validated entity.
+type EntityType string
+
+const (
+	EntityTypeSource                          EntityType = "source"
+	EntityTypeDestination                     EntityType = "destination"
+	EntityTypeConnection                      EntityType = "connection"
+	EntityTypeWHTProject                      EntityType = "whtProject"
+	EntityTypeSQLModelVersion                 EntityType = "sqlModelVersion"
+	EntityTypeSQLModelVersionSourceConnection EntityType = "sqlModelVersionSourceConnection"
+	EntityTypeAudienceSource                  EntityType = "audienceSource"
+)
+
+// Finding is a single problem with the config or the references of an entity.
+type Finding struct {
+	Severity Severity
+	// Field is the path of the field of the config the finding is about, e.g. "eventMapping[0].to", or empty if it is
+	// about the whole config. For references, it is the field of the entity holding the reference instead, see
+	// [ValidateReferences].
+	Field   string
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Field == "" {
+		return fmt.Sprintf("%s: %s", f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Field, f.Message)
+}
+
+// EntityReport holds the findings about an entity, sorted by field.
+type EntityReport struct {
+	WorkspaceID string
+	EntityType  EntityType
+	// EntityID is the ID of the entity, or its position in the list of the workspace for SQL model version source
+	// connections, which have no ID.
+	EntityID string
+	// DefinitionName is only set for sources and destinations.
+	DefinitionName string
+	Findings       []Finding
+}
+
+// HasErrors returns true if any of the findings is an error.
+func (r *EntityReport) HasErrors() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+// Report holds the reports of all the validated entities, sorted by workspace, entity type and entity ID.
+// Sources and destinations without findings are included too.
+type Report struct {
+	Entities []EntityReport
+}
+
+// HasErrors returns true if any of the entities has an error.
+func (r *Report) HasErrors() bool {
+	return slices.ContainsFunc(r.Entities, func(e EntityReport) bool { return e.HasErrors() })
+}
+
+// Validate validates the configs of all the sources and destinations of wcs against the definitions of wcs, and the
+// references of the entities of all the workspaces of wcs, see [ValidateReferences]. Deleted entities are skipped.
+func Validate(wcs *modelv2.WorkspaceConfigs) *Report {
+	r := &Report{}
+	for workspaceID, wc := range wcs.Workspaces {
+		if wc == nil {
+			continue
+		}
+		entities := make(map[entityKey]int)
+		for sourceID, source := range wc.Sources {
+			if source == nil || source.Deleted {
+				continue
+			}
+			entities[entityKey{entityType: EntityTypeSource, entityID: sourceID}] = len(r.Entities)
+			r.Entities = append(r.Entities, EntityReport{
+				WorkspaceID:    workspaceID,
+				EntityType:     EntityTypeSource,
+				EntityID:       sourceID,
+				DefinitionName: source.DefinitionName,
+				Findings:       ValidateSource(wcs.SourceDefinitions[source.DefinitionName], source),
+			})
+		}
+		for destinationID, destination := range wc.Destinations {
+			if destination == nil || destination.Deleted {
+				continue
+			}
+			entities[entityKey{entityType: EntityTypeDestination, entityID: destinationID}] = len(r.Entities)
+			r.Entities = append(r.Entities, EntityReport{
+				WorkspaceID:    workspaceID,
+				EntityType:     EntityTypeDestination,
+				EntityID:       destinationID,
+				DefinitionName: destination.DefinitionName,
+				Findings:       ValidateDestination(wcs.DestinationDefinitions[destination.DefinitionName], destination),
+			})
+		}
+		for _, e := range ValidateReferences(workspaceID, wc).Entities {
+			i, ok := entities[entityKey{entityType: e.EntityType, entityID: e.EntityID}]
+			if !ok {
+				r.Entities = append(r.Entities, e)
+				continue
+			}
+			r.Entities[i].Findings = append(r.Entities[i].Findings, e.Findings...)
+			slices.SortStableFunc(r.Entities[i].Findings, compareFindings)
+		}
+	}
+	sortEntities(r.Entities)
+	return r
+}
+
+// ValidateSource validates the config of a source against its definition, which may be nil if it is missing. A
+// missing definition is reported as a warning, since the config cannot be checked without it, e.g. when validating
+// a snapshot that doesn't carry all the definitions.
+func ValidateSource(def *modelv2.SourceDefinition, source *modelv2.Source) []Finding {
+	if def == nil {
+		return []Finding{{Severity: SeverityWarning, Message: fmt.Sprintf("source definition %q not found, config not validated", source.DefinitionName)}}
+	}
+	return validate(def.Config, source.Config)
+}
+
+// ValidateDestination validates the config of a destination against its definition, which may be nil if it is missing.
+// A missing definition is reported as a warning, like for [ValidateSource].
+func ValidateDestination(def *modelv2.DestinationDefinition, destination *modelv2.Destination) []Finding {
+	if def == nil {
+		return []Finding{{Severity: SeverityWarning, Message: fmt.Sprintf("destination definition %q not found, config not validated", destination.DefinitionName)}}
+	}
+	return validate(def.Config, destination.Config)
+}
+
+func validate(defConfig, config map[string]any) []Finding {
+	v := &validator{}
+	v.requiredKeys(defConfig, config)
+	v.secretKeys(defConfig, config)
+	v.connectionModes(defConfig, config)
+	v.knownKeys(defConfig, config)
+	if schema, ok := defConfig["configSchema"].(map[string]any); ok {
+		var c any // a nil map would otherwise be a non nil interface
+		if config != nil {
+			c = config
+		}
+		v.findings = append(v.findings, validateSchema("", schema, c)...)
+	}
+	slices.SortStableFunc(v.findings, compareFindings)
+	return slices.CompactFunc(v.findings, func(a, b Finding) bool { return a == b })
+}
+
+type validator struct {
+	findings []Finding
+}
+
+func (v *validator) errorf(field, format string, args ...any) {
+	v.findings = append(v.findings, Finding{Severity: SeverityError, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(field, format string, args ...any) {
+	v.findings = append(v.findings, Finding{Severity: SeverityWarning, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) requiredKeys(defConfig, config map[string]any) {
+	for _, key := range stringList(defConfig["requiredKeys"]) {
+		if value, ok := config[key]; !ok || value == nil || value == "" {
+			v.errorf(key, "required field is missing")
+		}
+	}
+}
+
+func (v *validator) secretKeys(defConfig, config map[string]any) {
+	for _, key := range stringList(defConfig["secretKeys"]) {
+		if value, ok := config[key]; ok && value != nil {
+			if _, ok := value.(string); !ok {
+				v.errorf(key, "secret must be a string, got %s", jsonType(value))
+			}
+		}
+	}
+}
+
+func (v *validator) connectionModes(defConfig, config map[string]any) {
+	supported, ok := defConfig["supportedConnectionModes"].(map[string]any)
+	if !ok {
+		return
+	}
+	modes, ok := config["connectionMode"].(map[string]any)
+	if !ok {
+		return
+	}
+	for sourceType, mode := range modes {
+		field := "connectionMode." + sourceType
+		allowed, ok := supported[sourceType]
+		if !ok {
+			v.errorf(field, "source type %q is not supported", sourceType)
+			continue
+		}
+		if !slices.Contains(stringList(allowed), fmt.Sprint(mode)) {
+			v.errorf(field, "connection mode %v is not one of %s", mode, strings.Join(stringList(allowed), ", "))
+		}
+	}
+}
+
+// knownKeys warns about the fields that are not listed by destConfig. Nothing is reported if destConfig has no
+// defaultConfig, since in that case it only lists the fields specific to some source types.
+func (v *validator) knownKeys(defConfig, config map[string]any) {
+	destConfig, ok := defConfig["destConfig"].(map[string]any)
+	if !ok {
+		return
+	}
+	if _, ok := destConfig["defaultConfig"]; !ok {
+		return
+	}
+	known := make(map[string]struct{})
+	for _, keys := range destConfig {
+		for _, key := range stringList(keys) {
+			known[key] = struct{}{}
+		}
+	}
+	for key := range config {
+		if _, ok := known[key]; !ok {
+			v.warnf(key, "unknown field")
+		}
+	}
+}
+
+// stringList returns the strings of a JSON array, skipping values of other types.
+func stringList(v any) []string {
+	items, _ := v.([]any)
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,172 @@
+package validation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/jsonrs"
+
+	"github.com/rudderlabs/rudder-cp-sdk/modelv2"
+	"github.com/rudderlabs/rudder-cp-sdk/validation"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("sample namespace", func(t *testing.T) {
+		data, err := os.ReadFile("../testdata/sample_namespace.json")
+		require.NoError(t, err)
+		wcs := &modelv2.WorkspaceConfigs{}
+		require.NoError(t, jsonrs.Unmarshal(data, wcs))
+
+		// the sample only contains the definitions of entities that are not part of it
+		r := validation.Validate(wcs)
+		require.False(t, r.HasErrors())
+		require.Len(t, r.Entities, 5)
+		require.Equal(t, validation.EntityReport{
+			WorkspaceID:    "2bVMV2JiAJe42OXZrzyvJI75v0N",
+			EntityType:     validation.EntityTypeDestination,
+			EntityID:       "2nqWMUk1fNNAw4rU3bHYcm58CZZ",
+			DefinitionName: "VWO",
+			Findings: []validation.Finding{
+				{Severity: validation.SeverityWarning, Message: `destination definition "VWO" not found, config not validated`},
+			},
+		}, r.Entities[0])
+	})
+
+	t.Run("definition rules", func(t *testing.T) {
+		wcs := &modelv2.WorkspaceConfigs{}
+		require.NoError(t, jsonrs.Unmarshal([]byte(`{
+			"workspaces": {
+				"ws-1": {
+					"sources": {
+						"src-1": {"sourceDefinitionName": "HTTP", "config": {"extra": true}},
+						"src-2": {"sourceDefinitionName": "HTTP", "deleted": true}
+					},
+					"destinations": {
+						"dst-1": {"destinationDefinitionName": "SFTP", "config": {
+							"host": "example.com",
+							"port": "22",
+							"password": 1234,
+							"connectionMode": {"warehouse": "device", "web": "cloud"},
+							"unknown": "field"
+						}},
+						"dst-2": {"destinationDefinitionName": "SFTP", "config": {
+							"host": "example.com",
+							"port": "22",
+							"password": "secret",
+							"connectionMode": {"warehouse": "cloud"}
+						}}
+					}
+				}
+			},
+			"sourceDefinitions": {"HTTP": {"config": null}},
+			"destinationDefinitions": {
+				"SFTP": {"config": {
+					"requiredKeys": ["host", "username"],
+					"secretKeys": ["password", "privateKey"],
+					"supportedConnectionModes": {"warehouse": ["cloud"]},
+					"destConfig": {
+						"warehouse": ["connectionMode"],
+						"defaultConfig": ["host", "port", "username", "password", "privateKey"]
+					}
+				}}
+			}
+		}`), wcs))
+		wcs.Workspaces["ws-1"].Destinations["dst-2"].Config["username"] = "user"
+
+		r := validation.Validate(wcs)
+		require.True(t, r.HasErrors())
+		require.Equal(t, []validation.EntityReport{
+			{
+				WorkspaceID:    "ws-1",
+				EntityType:     validation.EntityTypeDestination,
+				EntityID:       "dst-1",
+				DefinitionName: "SFTP",
+				Findings: []validation.Finding{
+					{Severity: validation.SeverityError, Field: "connectionMode.warehouse", Message: "connection mode device is not one of cloud"},
+					{Severity: validation.SeverityError, Field: "connectionMode.web", Message: `source type "web" is not supported`},
+					{Severity: validation.SeverityError, Field: "password", Message: "secret must be a string, got number"},
+					{Severity: validation.SeverityWarning, Field: "unknown", Message: "unknown field"},
+					{Severity: validation.SeverityError, Field: "username", Message: "required field is missing"},
+				},
+			},
+			{
+				WorkspaceID:    "ws-1",
+				EntityType:     validation.EntityTypeDestination,
+				EntityID:       "dst-2",
+				DefinitionName: "SFTP",
+			},
+			{
+				WorkspaceID:    "ws-1",
+				EntityType:     validation.EntityTypeSource,
+				EntityID:       "src-1",
+				DefinitionName: "HTTP",
+			},
+		}, r.Entities)
+	})
+}
+
+func TestValidateSchema(t *testing.T) {
+	def := &modelv2.DestinationDefinition{}
+	require.NoError(t, jsonrs.Unmarshal([]byte(`{"config": {"configSchema": {
+		"type": "object",
+		"required": ["apiKey"],
+		"additionalProperties": false,
+		"properties": {
+			"apiKey": {"type": "string", "pattern": "^[a-z0-9]+$", "minLength": 4},
+			"region": {"type": "string", "enum": ["US", "EU"]},
+			"batchSize": {"type": "integer", "minimum": 1, "maximum": 100},
+			"mapping": {"type": "array", "items": {"type": "object", "required": ["to"]}},
+			"mode": {"anyOf": [{"const": "cloud"}, {"const": "device"}]},
+			"endpoint": {"type": "string"}
+		},
+		"if": {"properties": {"region": {"const": "EU"}}},
+		"then": {"required": ["endpoint"]}
+	}}}`), def))
+
+	for _, tc := range []struct {
+		name     string
+		config   string
+		findings []validation.Finding
+	}{
+		{
+			name:   "valid",
+			config: `{"apiKey": "abcd", "region": "US", "batchSize": 10, "mapping": [{"to": "x"}], "mode": "cloud"}`,
+		},
+		{
+			name:   "nil config",
+			config: `null`,
+			findings: []validation.Finding{
+				{Severity: validation.SeverityError, Message: "expected object, got null"},
+			},
+		},
+		{
+			name:   "invalid",
+			config: `{"apiKey": "AB", "region": "APAC", "batchSize": 1.5, "mapping": [{}, {"to": 1}], "mode": "hybrid", "other": 1}`,
+			findings: []validation.Finding{
+				{Severity: validation.SeverityError, Field: "apiKey", Message: "length 2 is less than 4"},
+				{Severity: validation.SeverityError, Field: "apiKey", Message: `value "AB" does not match pattern "^[a-z0-9]+$"`},
+				{Severity: validation.SeverityError, Field: "batchSize", Message: "expected integer, got number"},
+				{Severity: validation.SeverityError, Field: "mapping[0].to", Message: "required field is missing"},
+				{Severity: validation.SeverityError, Field: "mode", Message: "value does not match any of the allowed schemas"},
+				{Severity: validation.SeverityError, Field: "other", Message: "unknown field"},
+				{Severity: validation.SeverityError, Field: "region", Message: `value "APAC" is not one of "US", "EU"`},
+			},
+		},
+		{
+			name:   "conditional",
+			config: `{"apiKey": "abcd", "region": "EU", "batchSize": 1000}`,
+			findings: []validation.Finding{
+				{Severity: validation.SeverityError, Field: "batchSize", Message: "value 1000 is greater than 100"},
+				{Severity: validation.SeverityError, Field: "endpoint", Message: "required field is missing"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			destination := &modelv2.Destination{DefinitionName: "TEST"}
+			require.NoError(t, jsonrs.Unmarshal([]byte(tc.config), &destination.Config))
+			require.Equal(t, tc.findings, validation.ValidateDestination(def, destination))
+		})
+	}
+}